@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"os"
+	"strconv"
 
 	"github.com/vitistack/common/pkg/clients/k8sclient"
 	"github.com/vitistack/common/pkg/clients/s3client/s3interface"
@@ -23,7 +24,11 @@ const (
 )
 
 func main() {
-	dotenv.LoadDotEnv()
+	// .env.example documents the variables below, the values they accept and which are
+	// required. Nothing is applied when the environment does not match it, so stop here.
+	if _, err := dotenv.Load(dotenv.Options{SearchUpwards: true, Schema: ".env.example"}); err != nil {
+		vlog.Fatal(err)
+	}
 
 	logJsonEnabled := os.Getenv("LOG_JSON_ENABLED") // example usage of an env var loaded from .env
 	vlog.Infof("LOG_JSON_ENABLED: %s", logJsonEnabled)
@@ -33,6 +38,8 @@ func main() {
 	vlog.Infof("LOG_ADD_CALLER: %s", logAddCaller)
 	logLevel := os.Getenv("LOG_LEVEL")
 	vlog.Infof("LOG_LEVEL: %s", logLevel)
+	logVerbosity, _ := strconv.Atoi(os.Getenv("LOG_VERBOSITY")) // validated as an int by the schema
+	vlog.Infof("LOG_VERBOSITY: %d", logVerbosity)
 	logUnescapeMultiline := os.Getenv("LOG_UNESCAPE_MULTILINE")
 	vlog.Infof("LOG_UNESCAPE_MULTILINE: %s", logUnescapeMultiline)
 	logDisableStacktrace := os.Getenv("LOG_DISABLE_STACKTRACE")
@@ -41,6 +48,7 @@ func main() {
 	// Initialize the logger
 	err := vlog.Setup(vlog.Options{
 		Level:             logLevel,                         // debug|info|warn|error|dpanic|panic|fatal
+		Verbosity:         logVerbosity,                     // highest logr V-level, like -v=3
 		ColorizeLine:      logColorizeEnabled == trueString, // whole-line color
 		JSON:              logJsonEnabled == trueString,     // console output (supports ANSI colors)
 		AddCaller:         logAddCaller == trueString,
@@ -0,0 +1,10 @@
+// Package crds embeds the vitistack.io CustomResourceDefinition manifests generated by
+// `make manifests`, so operators can install them without the Helm chart.
+package crds
+
+import "embed"
+
+// FS holds the generated CRD manifests (*.yaml).
+//
+//go:embed *.yaml
+var FS embed.FS
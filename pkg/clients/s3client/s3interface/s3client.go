@@ -6,6 +6,8 @@ import (
 	"context"
 	"io"
 	"time"
+
+	"github.com/vitistack/common/pkg/loggers/vlog"
 )
 
 type S3Client interface {
@@ -22,10 +24,12 @@ type S3Client interface {
 type Options struct {
 	Endpoint   string
 	Region     string
-	AccessKey  string // #nosec G117 -- configuration field, not a hardcoded secret
-	SecretKey  string // #nosec G117 -- configuration field, not a hardcoded secret
+	AccessKey  string `redact:"true"` // #nosec G117 -- configuration field, not a hardcoded secret
+	SecretKey  string `redact:"true"` // #nosec G117 -- configuration field, not a hardcoded secret
 	Secure     bool
 	BucketName string
+	// Logger receives the client's construction logs; vlog.Default() when nil.
+	Logger *vlog.SlogLogger `json:"-"`
 }
 
 type Option func(*Options)
@@ -77,3 +81,9 @@ func WithRegion(region string) Option {
 		o.Region = region
 	}
 }
+
+func WithLogger(logger *vlog.SlogLogger) Option {
+	return func(o *Options) {
+		o.Logger = logger
+	}
+}
@@ -18,12 +18,16 @@ type MinioS3Client struct {
 // NewS3Client creates a new MinioS3Client with the provided options
 // need to be called with options:
 // Required: Endpoint, AccessKey, SecretKey, Secure(https), BucketName
-// Optional: Region
+// Optional: Region, Logger
 func NewS3Client(opt ...s3interface.Option) (*MinioS3Client, error) {
 	var options s3interface.Options
 	for _, o := range opt {
 		o(&options)
 	}
+	log := options.Logger
+	if log == nil {
+		log = vlog.Default()
+	}
 	m, err := minio.New(options.Endpoint, &minio.Options{
 		Creds:  credentials.NewStaticV4(options.AccessKey, options.SecretKey, ""),
 		Secure: options.Secure,
@@ -31,7 +35,7 @@ func NewS3Client(opt ...s3interface.Option) (*MinioS3Client, error) {
 	})
 
 	if err != nil {
-		vlog.Errorf("Failed to create S3 client: %v", err)
+		log.Error("Failed to create S3 client", "endpoint", options.Endpoint, "error", err)
 		return nil, err
 	}
 
@@ -40,7 +44,7 @@ func NewS3Client(opt ...s3interface.Option) (*MinioS3Client, error) {
 		bucketName: options.BucketName,
 	}
 
-	vlog.Infof("S3 client created with endpoint: %s, bucket: %s", options.Endpoint, options.BucketName)
+	log.Info("S3 client created", "endpoint", options.Endpoint, "bucket", options.BucketName)
 	return s3client, nil
 }
 
@@ -48,18 +52,18 @@ func (c *MinioS3Client) GetObject(ctx context.Context, objectName string) ([]byt
 
 	object, err := c.client.GetObject(ctx, c.bucketName, objectName, minio.GetObjectOptions{})
 	if err != nil {
-		vlog.Warnf("Failed to get object: %v", err)
+		vlog.FromContext(ctx).Warn("Failed to get object", "bucket", c.bucketName, "object", objectName, "error", err)
 		return nil, err
 	}
 	defer func() {
 		if closeErr := object.Close(); closeErr != nil {
-			vlog.Warnf("Failed to close object: %v", closeErr)
+			vlog.FromContext(ctx).Warn("Failed to close object", "bucket", c.bucketName, "object", objectName, "error", closeErr)
 		}
 	}()
 
 	data, err := io.ReadAll(object)
 	if err != nil {
-		vlog.Errorf("Failed to read object data: %v", err)
+		vlog.FromContext(ctx).Error("Failed to read object data", "bucket", c.bucketName, "object", objectName, "error", err)
 		return nil, err
 	}
 
@@ -70,7 +74,7 @@ func (c *MinioS3Client) PutObject(ctx context.Context, objectName string, file i
 
 	_, err := c.client.PutObject(ctx, c.bucketName, objectName, file, size, minio.PutObjectOptions{})
 	if err != nil {
-		vlog.Warnf("Failed to put object: %v", err)
+		vlog.FromContext(ctx).Warn("Failed to put object", "bucket", c.bucketName, "object", objectName, "error", err)
 		return err
 	}
 
@@ -81,7 +85,7 @@ func (c *MinioS3Client) DeleteObject(ctx context.Context, objectName string) err
 
 	err := c.client.RemoveObject(ctx, c.bucketName, objectName, minio.RemoveObjectOptions{})
 	if err != nil {
-		vlog.Warnf("Failed to delete object: %v", err)
+		vlog.FromContext(ctx).Warn("Failed to delete object", "bucket", c.bucketName, "object", objectName, "error", err)
 		return err
 	}
 
@@ -98,7 +102,7 @@ func (c *MinioS3Client) ListObject(ctx context.Context, listOpt s3interface.List
 	var objects []s3interface.ObjectInfo
 	for object := range objectCh {
 		if object.Err != nil {
-			vlog.Warnf("Failed to list objects: %v", object.Err)
+			vlog.FromContext(ctx).Warn("Failed to list objects", "bucket", c.bucketName, "prefix", listOpt.Prefix, "error", object.Err)
 			return nil, object.Err
 		}
 		objects = append(objects, s3interface.ObjectInfo{
@@ -116,7 +120,7 @@ func (c *MinioS3Client) CreateBucket(ctx context.Context) error {
 
 	err := c.client.MakeBucket(ctx, c.bucketName, minio.MakeBucketOptions{})
 	if err != nil {
-		vlog.Warnf("Failed to create bucket: %v", err)
+		vlog.FromContext(ctx).Warn("Failed to create bucket", "bucket", c.bucketName, "error", err)
 		return err
 	}
 
@@ -127,7 +131,7 @@ func (c *MinioS3Client) DeleteBucket(ctx context.Context) error {
 
 	err := c.client.RemoveBucket(ctx, c.bucketName)
 	if err != nil {
-		vlog.Warnf("Failed to delete bucket: %v", err)
+		vlog.FromContext(ctx).Warn("Failed to delete bucket", "bucket", c.bucketName, "error", err)
 		return err
 	}
 
@@ -0,0 +1,37 @@
+package vlog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/go-logr/logr"
+)
+
+// IntoContext returns a copy of ctx that carries l. The logger is stored as a logr.Logger, so
+// controller-runtime's log.FromContext(ctx) returns the same logger.
+//
+//	ctx = vlog.IntoContext(ctx, vlog.FromContext(ctx).With("machine", m.Name))
+func IntoContext(ctx context.Context, l *SlogLogger) context.Context {
+	return logr.NewContext(ctx, l.Logr())
+}
+
+// FromContext returns the logger carried by ctx, or Default() if there is none. Values added
+// to it with With or logr's WithValues, such as the controller, name, namespace and reconcileID
+// that controller-runtime adds for every reconcile, are included in each record.
+func FromContext(ctx context.Context) *SlogLogger {
+	if ctx == nil {
+		return Default()
+	}
+	lr, err := logr.FromContext(ctx)
+	if err != nil {
+		return Default()
+	}
+	if s, ok := lr.GetSink().(*slogSink); ok {
+		l := *s.base
+		l.logger = s.slog()
+		return &l
+	}
+	// The logger was not created by vlog (or controller-runtime has not been given one yet),
+	// so write through it instead.
+	return &SlogLogger{logger: slog.New(logr.ToSlogHandler(lr))}
+}
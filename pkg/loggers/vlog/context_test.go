@@ -0,0 +1,64 @@
+package vlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestFromContext_ControllerRuntimeValues(t *testing.T) {
+	var buf bytes.Buffer
+	base := New(Options{Level: "info", JSON: true, AddCaller: true, Writer: &buf})
+
+	// controller-runtime adds these values to the logger of every reconcile.
+	ctx := ctrllog.IntoContext(context.Background(), base.Logr().WithValues(
+		"controller", "machine", "name", "m1", "namespace", "default", "reconcileID", "abc-123"))
+	ctx = IntoContext(ctx, FromContext(ctx).With("step", "provision"))
+
+	FromContext(ctx).Info("creating disks")
+	ctrllog.FromContext(ctx).Info("from controller-runtime")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 records, got %d:\n%s", len(lines), buf.String())
+	}
+	for _, line := range lines {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid JSON record %q: %v", line, err)
+		}
+		for k, want := range map[string]string{"reconcileID": "abc-123", "name": "m1", "namespace": "default", "step": "provision"} {
+			if rec[k] != want {
+				t.Errorf("%s = %v, want %q in %s", k, rec[k], want, line)
+			}
+		}
+	}
+	if !strings.Contains(lines[0], `"caller":"vlog/context_test.go:`) {
+		t.Errorf("caller should point at this test: %s", lines[0])
+	}
+}
+
+func TestFromContext_Fallbacks(t *testing.T) {
+	prev := Default()
+	defer SetDefault(prev)
+	var def bytes.Buffer
+	SetDefault(New(Options{Writer: &def}))
+
+	FromContext(context.Background()).Info("no logger in context")
+	if !strings.Contains(def.String(), "no logger in context") {
+		t.Errorf("expected the default logger to be used, got %q", def.String())
+	}
+
+	var out []string
+	foreign := funcr.New(func(prefix, args string) { out = append(out, args) }, funcr.Options{})
+	ctx := ctrllog.IntoContext(context.Background(), foreign.WithValues("reconcileID", "r1"))
+	FromContext(ctx).With("object", "o1").Warn("foreign sink")
+	if len(out) != 1 || !strings.Contains(out[0], `"reconcileID"="r1"`) || !strings.Contains(out[0], `"object"="o1"`) {
+		t.Errorf("expected the record to go through the context logger, got %q", out)
+	}
+}
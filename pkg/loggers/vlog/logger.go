@@ -0,0 +1,155 @@
+package vlog
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// SlogLogger is an independent vlog logger. Libraries and tests can create their own with New
+// instead of sharing the package-level default.
+//
+//	var buf bytes.Buffer
+//	log := vlog.New(vlog.Options{Level: "debug", Writer: &buf})
+//	log.With("machine", name).Infof("created in %s", elapsed)
+type SlogLogger struct {
+	logger    *slog.Logger
+	addCaller bool
+	out       io.Writer
+}
+
+// New creates a SlogLogger from opts. Output goes to opts.Writer, or os.Stdout when nil.
+func New(opts Options) *SlogLogger {
+	out := opts.Writer
+	if out == nil {
+		out = os.Stdout
+	}
+	level := slogLevelFromString(opts.Level).Level()
+	if opts.Verbosity > 0 {
+		level = min(level, vLevel(opts.Verbosity))
+	}
+	handlerOpts := &slog.HandlerOptions{
+		AddSource: false, // we add caller manually to control the skip depth
+		Level:     level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// Format time as RFC3339 to match previous output style
+			if a.Key == slog.TimeKey {
+				if t, ok := a.Value.Any().(time.Time); ok {
+					a.Value = slog.StringValue(t.Format(time.RFC3339))
+				}
+			}
+			// logr V-levels sit between Debug and Info (or below Debug); print them as DEBUG
+			// rather than DEBUG+3, the "v" field carries the verbosity.
+			if a.Key == slog.LevelKey && len(groups) == 0 {
+				if lvl, ok := a.Value.Any().(slog.Level); ok && lvl < slog.LevelInfo {
+					a.Value = slog.StringValue(slog.LevelDebug.String())
+				}
+			}
+			return a
+		},
+	}
+
+	var h slog.Handler
+	switch {
+	case opts.JSON:
+		h = slog.NewJSONHandler(out, handlerOpts)
+	case opts.ColorizeLine:
+		h = newColorTextHandler(out, handlerOpts, opts.UnescapeMultiline)
+	default:
+		h = newPlainTextHandler(out, handlerOpts, opts.UnescapeMultiline)
+	}
+	return &SlogLogger{logger: slog.New(h), addCaller: opts.AddCaller, out: out}
+}
+
+// defaultLogger backs the package-level functions; it is created lazily and replaced by Setup.
+var defaultLogger atomic.Pointer[SlogLogger]
+
+// Default returns the logger used by the package-level functions. Until Setup or SetDefault is
+// called it logs JSON at info level to os.Stdout.
+func Default() *SlogLogger {
+	if l := defaultLogger.Load(); l != nil {
+		return l
+	}
+	defaultLogger.CompareAndSwap(nil, New(Options{Level: "info", JSON: true}))
+	return defaultLogger.Load()
+}
+
+// SetDefault replaces the logger used by the package-level functions. It is safe to call
+// concurrently with logging.
+func SetDefault(l *SlogLogger) {
+	if l != nil {
+		defaultLogger.Store(l)
+	}
+}
+
+// Debug logs at Debug level. The first argument is the message, the rest are key-value pairs.
+func (l *SlogLogger) Debug(args ...any) { l.logArgs(slog.LevelDebug, args...) }
+
+// Info logs at Info level. The first argument is the message, the rest are key-value pairs.
+func (l *SlogLogger) Info(args ...any) { l.logArgs(slog.LevelInfo, args...) }
+
+// Warn logs at Warn level. The first argument is the message, the rest are key-value pairs.
+func (l *SlogLogger) Warn(args ...any) { l.logArgs(slog.LevelWarn, args...) }
+
+// Error logs at Error level. The first argument is the message, the rest are key-value pairs.
+func (l *SlogLogger) Error(args ...any) { l.logArgs(slog.LevelError, args...) }
+
+// Formatted variants.
+func (l *SlogLogger) Debugf(format string, args ...any) {
+	l.logMsg(slog.LevelDebug, fmt.Sprintf(format, args...))
+}
+func (l *SlogLogger) Infof(format string, args ...any) {
+	l.logMsg(slog.LevelInfo, fmt.Sprintf(format, args...))
+}
+func (l *SlogLogger) Warnf(format string, args ...any) {
+	l.logMsg(slog.LevelWarn, fmt.Sprintf(format, args...))
+}
+func (l *SlogLogger) Errorf(format string, args ...any) {
+	l.logMsg(slog.LevelError, fmt.Sprintf(format, args...))
+}
+
+// With returns a child logger that adds the key-value pairs to every record.
+func (l *SlogLogger) With(keysAndValues ...any) *SlogLogger {
+	child := *l
+	child.logger = l.logger.With(convertKVs(keysAndValues)...)
+	return &child
+}
+
+// Slog returns the underlying slog.Logger.
+func (l *SlogLogger) Slog() *slog.Logger { return l.logger }
+
+// Sugar returns the logger as a SugaredLogger, which implements loggers.Logger.
+func (l *SlogLogger) Sugar() *SugaredLogger { return &SugaredLogger{logger: l.logger} }
+
+// Logr returns a logr.Logger backed by this logger, for controller-runtime integration.
+func (l *SlogLogger) Logr() logr.Logger { return logr.New(&slogSink{base: l}) }
+
+// Sync flushes the writer if it supports it, e.g. an *os.File.
+func (l *SlogLogger) Sync() error {
+	if s, ok := l.out.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+func (l *SlogLogger) logArgs(level slog.Level, args ...any) {
+	if len(args) == 0 {
+		return
+	}
+	// First argument is the message, remaining args are key-value pairs
+	msg := fmt.Sprint(args[0])
+	if len(args) > 1 {
+		l.writeRecordWithAttrs(level, msg, args[1:]...)
+	} else {
+		l.writeRecord(level, msg)
+	}
+}
+
+func (l *SlogLogger) logMsg(level slog.Level, msg string) {
+	l.writeRecord(level, msg)
+}
@@ -0,0 +1,194 @@
+package vlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestNew_WritesToWriter(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(Options{Level: "info", JSON: true, AddCaller: true, Writer: &buf})
+
+	l.Debug("hidden")
+	l.With("machine", "m1").Info("created", "zone", "a")
+	l.Warnf("retry %d", 2)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 records, got %d:\n%s", len(lines), buf.String())
+	}
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatalf("invalid JSON record %q: %v", lines[0], err)
+	}
+	if rec["msg"] != "created" || rec["machine"] != "m1" || rec["zone"] != "a" {
+		t.Errorf("unexpected record: %v", rec)
+	}
+	if caller, _ := rec["caller"].(string); !strings.HasPrefix(caller, "vlog/logger_test.go:") {
+		t.Errorf("caller = %q, want this test file", caller)
+	}
+	if !strings.Contains(lines[1], `"msg":"retry 2"`) {
+		t.Errorf("unexpected record: %s", lines[1])
+	}
+}
+
+func TestNew_IndependentLoggers(t *testing.T) {
+	var a, b bytes.Buffer
+	la := New(Options{Level: "debug", Writer: &a})
+	lb := New(Options{Level: "error", Writer: &b, UnescapeMultiline: true})
+
+	la.Debug("to a")
+	lb.Info("dropped")
+	lb.Error("to b")
+
+	if !strings.Contains(a.String(), "to a") || strings.Contains(a.String(), "to b") {
+		t.Errorf("a = %q", a.String())
+	}
+	if strings.Contains(b.String(), "dropped") || !strings.Contains(b.String(), "to b") {
+		t.Errorf("b = %q", b.String())
+	}
+}
+
+func TestSetup_ConcurrentWithLogging(t *testing.T) {
+	prev := Default()
+	defer SetDefault(prev)
+
+	var buf syncBuffer
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = Setup(Options{Level: "info", JSON: i%2 == 0, Writer: &buf})
+		}()
+		go func() {
+			defer wg.Done()
+			Infof("message %d", i)
+			With("i", i).Info("with")
+		}()
+	}
+	wg.Wait()
+
+	var out bytes.Buffer
+	SetDefault(New(Options{Writer: &out}))
+	Info("after")
+	if !strings.Contains(out.String(), "after") {
+		t.Errorf("package-level functions should use the new default, got %q", out.String())
+	}
+}
+
+func TestLogr_Verbosity(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      Options
+		logged    []int
+		notLogged []int
+	}{
+		{name: "info", opts: Options{Level: "info"}, logged: []int{0}, notLogged: []int{1}},
+		{name: "debug", opts: Options{Level: "debug"}, logged: []int{0, 1, 4}, notLogged: []int{5}},
+		{name: "verbosity 2", opts: Options{Level: "info", Verbosity: 2}, logged: []int{0, 1, 2}, notLogged: []int{3}},
+		{name: "verbosity 8", opts: Options{Level: "info", Verbosity: 8}, logged: []int{4, 8}, notLogged: []int{9}},
+		{name: "error level", opts: Options{Level: "error"}, notLogged: []int{0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.opts.JSON, tt.opts.Writer = true, &buf
+			lr := New(tt.opts).Logr()
+			for _, v := range tt.logged {
+				if !lr.V(v).Enabled() {
+					t.Errorf("V(%d) should be enabled", v)
+				}
+			}
+			for _, v := range tt.notLogged {
+				if lr.V(v).Enabled() {
+					t.Errorf("V(%d) should not be enabled", v)
+				}
+			}
+		})
+	}
+}
+
+func TestLogr_VerbosityOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		json bool
+		want []string
+	}{
+		{
+			name: "text",
+			want: []string{`level=INFO msg=v0`, `level=DEBUG msg=v1 v=1`, `level=DEBUG msg=v4 v=4`},
+		},
+		{
+			name: "json",
+			json: true,
+			want: []string{`"level":"INFO","msg":"v0"}`, `"level":"DEBUG","msg":"v1","v":1}`, `"level":"DEBUG","msg":"v4","v":4}`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			lr := New(Options{Verbosity: 4, JSON: tt.json, Writer: &buf}).Logr()
+			for _, v := range []int{0, 1, 4, 5} {
+				lr.V(v).Info(fmt.Sprintf("v%d", v))
+			}
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("expected %d records, got %d:\n%s", len(tt.want), len(lines), buf.String())
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(lines[i], want) {
+					t.Errorf("record %d = %s, want %s", i, lines[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestLogr_NameAndErrors(t *testing.T) {
+	err := &fs.PathError{Op: "open", Path: "/etc/vitistack", Err: errors.New("no such file")}
+	want := "open /etc/vitistack: no such file"
+
+	var jsonBuf bytes.Buffer
+	New(Options{JSON: true, Writer: &jsonBuf}).Logr().WithName("controller").WithName("machine").
+		WithValues("reconcileID", "r1").Error(err, "reconcile failed", "cause", err)
+	var rec map[string]any
+	if jerr := json.Unmarshal(jsonBuf.Bytes(), &rec); jerr != nil {
+		t.Fatalf("invalid JSON record %q: %v", jsonBuf.String(), jerr)
+	}
+	if rec["logger"] != "controller/machine" || rec["reconcileID"] != "r1" {
+		t.Errorf("record = %v, want top-level logger and reconcileID", rec)
+	}
+	if rec["error"] != want || rec["cause"] != want {
+		t.Errorf("error = %v, cause = %v, want %q", rec["error"], rec["cause"], want)
+	}
+
+	var textBuf bytes.Buffer
+	New(Options{Writer: &textBuf}).Logr().WithName("controller").Error(err, "reconcile failed")
+	if !strings.Contains(textBuf.String(), `logger=controller`) || !strings.Contains(textBuf.String(), `error="`+want+`"`) {
+		t.Errorf("text record = %s", textBuf.String())
+	}
+
+	var nilErr *fs.PathError
+	if got := errorString(nilErr); got != "<nil>" {
+		t.Errorf("errorString(typed nil) = %q, want <nil>", got)
+	}
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
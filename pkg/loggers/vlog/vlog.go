@@ -27,6 +27,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 	"sync"
@@ -38,15 +39,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/vitistack/common/pkg/loggers"
-	"github.com/vitistack/common/pkg/serialize"
-)
-
-// Package-level logger with lazy default initialization.
-var (
-	base       *slog.Logger
-	once       sync.Once
-	addCaller  bool
-	doUnescape bool
+	"github.com/vitistack/common/pkg/redact"
 )
 
 // Options configures the vlog logger (now backed by Go's slog).
@@ -67,126 +60,97 @@ type Options struct {
 	// msg="..." into real multi-line output (removing surrounding quotes). Adds a small per-log overhead.
 	// Default: false (favor performance); can be enabled when human readability of large multi-line messages matters.
 	UnescapeMultiline bool
+	// Writer receives the log output. Default: os.Stdout.
+	Writer io.Writer
+	// Verbosity is the highest logr V-level that is logged, like -v=3. V(n) is logged at slog
+	// level -n, so a Verbosity of 4 or more also enables Debug. Default: 0, where Level decides.
+	Verbosity int
 }
 
-// Setup initializes the global slog-based logger with the provided options.
+// Setup replaces the default logger used by the package-level functions with New(opts).
+// It is safe to call concurrently with logging and with other Setup calls.
 func Setup(opts Options) error {
-	addCaller = opts.AddCaller
-	doUnescape = opts.UnescapeMultiline
-	handlerOpts := &slog.HandlerOptions{
-		AddSource: false, // we add caller manually to control the skip depth
-		Level:     slogLevelFromString(opts.Level),
-		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
-			// Format time as RFC3339 to match previous output style
-			if a.Key == slog.TimeKey {
-				if t, ok := a.Value.Any().(time.Time); ok {
-					a.Value = slog.StringValue(t.Format(time.RFC3339))
-				}
-			}
-			return a
-		},
-	}
-
-	var h slog.Handler
-	switch {
-	case opts.JSON:
-		h = slog.NewJSONHandler(os.Stdout, handlerOpts)
-	case opts.ColorizeLine:
-		h = newColorTextHandler(os.Stdout, handlerOpts)
-	default:
-		h = newPlainTextHandler(os.Stdout, handlerOpts)
-	}
-
-	base = slog.New(h)
+	SetDefault(New(opts))
 	return nil
 }
 
-// ensure ensures the logger is initialized with sensible defaults.
-func ensure() {
-	if base != nil {
-		return
-	}
-	once.Do(func() {
-		if base != nil {
-			return
-		}
-		_ = Setup(Options{ // defaults: JSON (efficient), info level
-			Level:             "info",
-			JSON:              true,
-			AddCaller:         false,
-			ColorizeLine:      false,
-			UnescapeMultiline: false,
-		})
-	})
-}
-
-// Sync is kept for API compatibility; slog's standard handlers don't buffer.
-func Sync() error { return nil }
-
-// Logr returns a logr.Logger backed by the slog logger, for controller-runtime integration.
-func Logr() logr.Logger {
-	ensure()
-	sink := &slogSink{logger: base}
-	return logr.New(sink)
+// Sync flushes the default logger's writer when it supports it; errors are ignored since
+// terminals and pipes cannot be synced.
+func Sync() error {
+	_ = Default().Sync()
+	return nil
 }
 
-// Logger returns the generic loggers.Logger backed by this package's slog logger.
-func Logger() loggers.Logger {
-	ensure()
-	return &SugaredLogger{logger: base}
-}
+// Logr returns a logr.Logger backed by the default logger, for controller-runtime integration.
+func Logr() logr.Logger { return Default().Logr() }
 
 // Debug logs at Debug level. Accepts mixed arguments.
-func Debug(args ...any) { logArgs(slog.LevelDebug, args...) }
+func Debug(args ...any) { Default().logArgs(slog.LevelDebug, args...) }
 
 // Info logs at Info level. Accepts mixed arguments.
-func Info(args ...any) { logArgs(slog.LevelInfo, args...) }
+func Info(args ...any) { Default().logArgs(slog.LevelInfo, args...) }
 
 // Warn logs at Warn level. Accepts mixed arguments.
-func Warn(args ...any) { logArgs(slog.LevelWarn, args...) }
+func Warn(args ...any) { Default().logArgs(slog.LevelWarn, args...) }
 
 // Error logs at Error level. Accepts mixed arguments.
-func Error(args ...any) { logArgs(slog.LevelError, args...) }
+func Error(args ...any) { Default().logArgs(slog.LevelError, args...) }
 
 // DPanic logs at Error level (closest mapping in slog).
-func DPanic(args ...any) { logArgs(slog.LevelError, args...) }
+func DPanic(args ...any) { Default().logArgs(slog.LevelError, args...) }
 
 // Panic logs at Error level then panics.
-func Panic(args ...any) { logArgs(slog.LevelError, args...); panic(fmt.Sprint(args...)) }
+func Panic(args ...any) { Default().logArgs(slog.LevelError, args...); panic(fmt.Sprint(args...)) }
 
 // Fatal logs at Error level then exits(1).
-// Syncs stdout before exiting to ensure logs are flushed in containerized environments.
+// Syncs the output before exiting to ensure logs are flushed in containerized environments.
 func Fatal(args ...any) {
-	logArgs(slog.LevelError, args...)
-	_ = os.Stdout.Sync()
+	l := Default()
+	l.logArgs(slog.LevelError, args...)
+	_ = l.Sync()
 	os.Exit(1)
 }
 
 // Formatted variants.
-func Debugf(format string, args ...any)  { logMsg(slog.LevelDebug, fmt.Sprintf(format, args...)) }
-func Infof(format string, args ...any)   { logMsg(slog.LevelInfo, fmt.Sprintf(format, args...)) }
-func Warnf(format string, args ...any)   { logMsg(slog.LevelWarn, fmt.Sprintf(format, args...)) }
-func Errorf(format string, args ...any)  { logMsg(slog.LevelError, fmt.Sprintf(format, args...)) }
-func DPanicf(format string, args ...any) { logMsg(slog.LevelError, fmt.Sprintf(format, args...)) }
+func Debugf(format string, args ...any) {
+	Default().logMsg(slog.LevelDebug, fmt.Sprintf(format, args...))
+}
+func Infof(format string, args ...any) {
+	Default().logMsg(slog.LevelInfo, fmt.Sprintf(format, args...))
+}
+func Warnf(format string, args ...any) {
+	Default().logMsg(slog.LevelWarn, fmt.Sprintf(format, args...))
+}
+func Errorf(format string, args ...any) {
+	Default().logMsg(slog.LevelError, fmt.Sprintf(format, args...))
+}
+func DPanicf(format string, args ...any) {
+	Default().logMsg(slog.LevelError, fmt.Sprintf(format, args...))
+}
 func Panicf(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
-	logMsg(slog.LevelError, msg)
+	Default().logMsg(slog.LevelError, msg)
 	panic(msg)
 }
 
 // Fatalf logs at Error level then exits(1).
-// Syncs stdout before exiting to ensure logs are flushed in containerized environments.
+// Syncs the output before exiting to ensure logs are flushed in containerized environments.
 func Fatalf(format string, args ...any) {
-	logMsg(slog.LevelError, fmt.Sprintf(format, args...))
-	_ = os.Stdout.Sync()
+	l := Default()
+	l.logMsg(slog.LevelError, fmt.Sprintf(format, args...))
+	_ = l.Sync()
 	os.Exit(1)
 }
 
 // With returns a child logger with additional structured context provided as key-value pairs.
 // Example: vlog.With("pod", podName, "ns", namespace).Info("created")
 func With(keysAndValues ...any) *SugaredLogger {
-	ensure()
-	return &SugaredLogger{logger: base.With(convertKVs(keysAndValues)...)}
+	return Default().With(keysAndValues...).Sugar()
+}
+
+// Logger returns the generic loggers.Logger backed by the default logger.
+func Logger() loggers.Logger {
+	return Default().Sugar()
 }
 
 // SugaredLogger provides chainable methods similar to zap's SugaredLogger.
@@ -228,28 +192,9 @@ var _ loggers.Logger = (*SugaredLogger)(nil)
 
 // --- helpers ---
 
-func logArgs(level slog.Level, args ...any) {
-	ensure()
-	if len(args) == 0 {
-		return
-	}
-	// First argument is the message, remaining args are key-value pairs
-	msg := fmt.Sprint(args[0])
-	if len(args) > 1 {
-		writeRecordWithAttrs(base, level, msg, args[1:]...)
-	} else {
-		writeRecord(base, level, msg)
-	}
-}
-
-func logMsg(level slog.Level, msg string) {
-	ensure()
-	writeRecord(base, level, msg)
-}
-
 // writeRecord constructs a slog.Record with a caller pointing at the first frame outside this package.
-func writeRecord(logger *slog.Logger, level slog.Level, msg string) {
-	h := logger.Handler()
+func (l *SlogLogger) writeRecord(level slog.Level, msg string) {
+	h := l.logger.Handler()
 	// Check if this level is enabled before proceeding
 	if !h.Enabled(context.Background(), level) {
 		return
@@ -257,11 +202,11 @@ func writeRecord(logger *slog.Logger, level slog.Level, msg string) {
 	pc := uintptr(0)
 	file := ""
 	line := 0
-	if addCaller {
+	if l.addCaller {
 		pc, file, line = findExternalCaller()
 	}
 	rec := slog.NewRecord(time.Now(), level, msg, pc)
-	if addCaller && file != "" {
+	if l.addCaller && file != "" {
 		short := shortenPath(file)
 		rec.AddAttrs(slog.String("caller", fmt.Sprintf("%s:%d", short, line)))
 	}
@@ -269,8 +214,8 @@ func writeRecord(logger *slog.Logger, level slog.Level, msg string) {
 }
 
 // writeRecordWithAttrs constructs a slog.Record with key-value attributes.
-func writeRecordWithAttrs(logger *slog.Logger, level slog.Level, msg string, keysAndValues ...any) {
-	h := logger.Handler()
+func (l *SlogLogger) writeRecordWithAttrs(level slog.Level, msg string, keysAndValues ...any) {
+	h := l.logger.Handler()
 	// Check if this level is enabled before proceeding
 	if !h.Enabled(context.Background(), level) {
 		return
@@ -278,11 +223,11 @@ func writeRecordWithAttrs(logger *slog.Logger, level slog.Level, msg string, key
 	pc := uintptr(0)
 	file := ""
 	line := 0
-	if addCaller {
+	if l.addCaller {
 		pc, file, line = findExternalCaller()
 	}
 	rec := slog.NewRecord(time.Now(), level, msg, pc)
-	if addCaller && file != "" {
+	if l.addCaller && file != "" {
 		short := shortenPath(file)
 		rec.AddAttrs(slog.String("caller", fmt.Sprintf("%s:%d", short, line)))
 	}
@@ -307,7 +252,7 @@ func findExternalCaller() (uintptr, string, int) {
 	frames := runtime.CallersFrames(pcs[:n])
 	for {
 		fr, more := frames.Next()
-		if fr.Function == "" || !strings.Contains(fr.Function, "/pkg/loggers/vlog.") {
+		if fr.Function == "" || !strings.Contains(fr.Function, "/pkg/loggers/vlog.") || strings.HasSuffix(fr.File, "_test.go") {
 			return fr.PC, fr.File, fr.Line
 		}
 		if !more {
@@ -350,15 +295,21 @@ func convertKVs(kv []any) []any {
 	if len(kv)%2 == 1 {
 		kv = append(kv, "<missing>")
 	}
-	// Auto-format JSON structures with indentation
+	// Auto-format JSON structures with indentation and redact sensitive values
 	for i := 1; i < len(kv); i += 2 {
+		if key, ok := kv[i-1].(string); ok && redact.Enabled() && redact.IsSensitiveKey(key) {
+			if s, isString := kv[i].(string); isString && s != "" {
+				kv[i] = redact.Redacted
+				continue
+			}
+		}
 		kv[i] = autoFormatJSON(kv[i])
 	}
 	return kv
 }
 
 // autoFormatJSON attempts to detect and pretty-print JSON-like structures (maps, slices, structs)
-// with 2-space indentation using serialize.Pretty(). Returns the original value if not applicable.
+// with 2-space indentation. Returns the original value if not applicable.
 func autoFormatJSON(v any) any {
 	if v == nil {
 		return nil
@@ -369,6 +320,11 @@ func autoFormatJSON(v any) any {
 		return v
 	}
 
+	// Errors are logged as their message, not as JSON of the error struct
+	if err, ok := v.(error); ok {
+		return errorString(err)
+	}
+
 	// If it's a string that looks like JSON, try to reformat it
 	if s, ok := v.(string); ok {
 		trimmed := strings.TrimSpace(s)
@@ -381,21 +337,38 @@ func autoFormatJSON(v any) any {
 		return v
 	}
 
-	// For maps, slices, and structs, use serialize.Pretty()
+	// For maps, slices, and structs, pretty-print as indented JSON
 	switch v.(type) {
 	case map[string]any, []any, map[string]string, map[string]int,
 		[]string, []int, []map[string]any:
-		return serialize.Pretty(v)
+		return indentJSON(v)
 	default:
 		// Check if it's a struct (not a basic type)
 		if isStructType(v) {
-			return serialize.Pretty(v)
+			return indentJSON(v)
 		}
 	}
 
 	return v
 }
 
+// indentJSON returns v as redacted JSON with 2-space indentation, or fmt's form of v on error.
+func indentJSON(v any) string {
+	b, err := json.MarshalIndent(redacted(v), "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v (serialize error: %v)", v, err)
+	}
+	return string(b)
+}
+
+// errorString returns err.Error(), or "<nil>" for a typed nil pointer.
+func errorString(err error) string {
+	if v := reflect.ValueOf(err); v.Kind() == reflect.Pointer && v.IsNil() {
+		return "<nil>"
+	}
+	return err.Error()
+}
+
 // isStructType checks if the value is a struct or pointer to struct
 func isStructType(v any) bool {
 	if v == nil {
@@ -450,11 +423,12 @@ func (p prettyValue) String() string {
 	}
 
 	// Try JSON marshal with indent first.
-	if b, err := json.MarshalIndent(p.v, "", "  "); err == nil {
+	v := redacted(p.v)
+	if b, err := json.MarshalIndent(v, "", "  "); err == nil {
 		return string(b)
 	}
 	// Try YAML marshal.
-	if b, err := yaml.Marshal(p.v); err == nil {
+	if b, err := yaml.Marshal(v); err == nil {
 		return string(b)
 	}
 	// Fallback verbose formatting.
@@ -466,7 +440,7 @@ func reformatJSONBytes(b []byte) (string, bool) {
 	if err := json.Unmarshal(b, &anyVal); err != nil {
 		return "", false
 	}
-	out, err := json.MarshalIndent(anyVal, "", "  ")
+	out, err := json.MarshalIndent(redacted(anyVal), "", "  ")
 	if err != nil {
 		return "", false
 	}
@@ -479,13 +453,21 @@ func reformatYAMLBytes(b []byte) (string, bool) {
 		return "", false
 	}
 	// Marshal back to YAML (yaml lib already emits multi-line with indentation)
-	out, err := yaml.Marshal(anyVal)
+	out, err := yaml.Marshal(redacted(anyVal))
 	if err != nil {
 		return "", false
 	}
 	return string(out), true
 }
 
+// redacted applies redact.Value unless redaction has been disabled with redact.SetEnabled.
+func redacted(v any) any {
+	if !redact.Enabled() {
+		return v
+	}
+	return redact.Value(v)
+}
+
 func looksLikeYAML(s string) bool {
 	// Heuristic: contains ':' early (key: value) and not pure JSON braces.
 	if strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[") {
@@ -511,8 +493,8 @@ const (
 
 func levelColorSlog(lvl slog.Level) string {
 	switch {
-	case lvl <= slog.LevelDebug:
-		return ansiBlue
+	case lvl < slog.LevelInfo:
+		return ansiBlue // debug and logr V-levels
 	case lvl < slog.LevelWarn:
 		return ansiGreen // info
 	case lvl < slog.LevelError:
@@ -530,8 +512,8 @@ type plainTextHandler struct {
 	unescape bool
 }
 
-func newPlainTextHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
-	if !doUnescape {
+func newPlainTextHandler(w io.Writer, opts *slog.HandlerOptions, unescape bool) slog.Handler {
+	if !unescape {
 		return slog.NewTextHandler(w, opts)
 	}
 	return &plainTextHandler{w: &syncWriter{w: w}, opts: opts, unescape: true}
@@ -603,14 +585,15 @@ func (s *syncWriter) WriteString(str string) (int, error) {
 }
 
 type colorTextHandler struct {
-	w      *syncWriter
-	opts   *slog.HandlerOptions
-	attrs  []slog.Attr
-	groups []string
+	w        *syncWriter
+	opts     *slog.HandlerOptions
+	attrs    []slog.Attr
+	groups   []string
+	unescape bool
 }
 
-func newColorTextHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
-	return &colorTextHandler{w: &syncWriter{w: w}, opts: opts}
+func newColorTextHandler(w io.Writer, opts *slog.HandlerOptions, unescape bool) slog.Handler {
+	return &colorTextHandler{w: &syncWriter{w: w}, opts: opts, unescape: unescape}
 }
 
 func (h *colorTextHandler) Enabled(ctx context.Context, level slog.Level) bool {
@@ -639,7 +622,7 @@ func (h *colorTextHandler) Handle(ctx context.Context, r slog.Record) error {
 	}
 	b := buf.Bytes()
 	// Optional multiline unescape (enabled only when UnescapeMultiline option is set and using text mode).
-	if doUnescape {
+	if h.unescape {
 		b = unescapeMultilineAttrs(b)
 	}
 	color := levelColorSlog(r.Level)
@@ -777,62 +760,62 @@ func rebuildMultilineValue(orig []byte, eqIdx, end int, expanded []byte) []byte
 	return rebuilt.Bytes()
 }
 
-// slogSink adapts slog.Logger to logr.LogSink for controller-runtime compatibility.
+// slogSink adapts a SlogLogger to logr.LogSink for controller-runtime compatibility.
 type slogSink struct {
-	logger *slog.Logger
-	name   string
-	kv     []any
+	base *SlogLogger
+	name string
+	kv   []any
 }
 
 func (s *slogSink) Init(_ logr.RuntimeInfo) {}
 
 func (s *slogSink) Enabled(level int) bool {
-	// logr calls Enabled with a verbosity level: V(0) -> Info, V(1+) -> Debug.
-	// Map to slog levels and delegate to the handler's Enabled check.
-	lvl := slog.LevelInfo
-	if level > 0 {
-		lvl = slog.LevelDebug
-	}
-	return s.logger.Handler().Enabled(context.Background(), lvl)
+	return s.base.logger.Handler().Enabled(context.Background(), vLevel(level))
 }
 
 func (s *slogSink) Info(level int, msg string, keysAndValues ...any) {
-	l := s.logger
-	if s.name != "" {
-		l = l.WithGroup(s.name)
-	}
-	if len(s.kv) > 0 {
-		l = l.With(convertKVs(s.kv)...)
-	}
-	// Map logr verbosity: V(0) -> Info, V(1+) -> Debug.
-	lvl := slog.LevelInfo
+	attrs := convertKVs(keysAndValues)
 	if level > 0 {
-		lvl = slog.LevelDebug
+		attrs = append(attrs, "v", level)
 	}
-	l.Log(context.Background(), lvl, msg, convertKVs(keysAndValues)...)
+	s.slog().Log(context.Background(), vLevel(level), msg, attrs...)
 }
 
 func (s *slogSink) Error(err error, msg string, keysAndValues ...any) {
-	l := s.logger
-	if s.name != "" {
-		l = l.WithGroup(s.name)
-	}
-	if len(s.kv) > 0 {
-		l = l.With(convertKVs(s.kv)...)
+	attrs := convertKVs(keysAndValues)
+	if err != nil {
+		attrs = append(attrs, "error", errorString(err))
 	}
-	attrs := append(convertKVs(keysAndValues), "err", err)
-	l.Log(context.Background(), slog.LevelError, msg, attrs...)
+	s.slog().Log(context.Background(), slog.LevelError, msg, attrs...)
 }
 
 func (s *slogSink) WithValues(keysAndValues ...any) logr.LogSink {
-	return &slogSink{logger: s.logger, name: s.name, kv: append(append([]any(nil), s.kv...), keysAndValues...)}
+	return &slogSink{base: s.base, name: s.name, kv: append(append([]any(nil), s.kv...), keysAndValues...)}
 }
 
 func (s *slogSink) WithName(name string) logr.LogSink {
-	// Chain groups using '/'
+	// Chain names using '/'
 	newName := name
 	if s.name != "" {
 		newName = s.name + "/" + name
 	}
-	return &slogSink{logger: s.logger, name: newName, kv: append([]any(nil), s.kv...)}
+	return &slogSink{base: s.base, name: newName, kv: append([]any(nil), s.kv...)}
+}
+
+// slog returns the base logger with the sink's name and values applied. The name is a plain
+// "logger" field so the values stay at the top level of the record.
+func (s *slogSink) slog() *slog.Logger {
+	l := s.base.logger
+	if s.name != "" {
+		l = l.With("logger", s.name)
+	}
+	if len(s.kv) > 0 {
+		l = l.With(convertKVs(s.kv)...)
+	}
+	return l
+}
+
+// vLevel maps a logr V-level to a slog level: V(0) is Info and V(n) is Info-n, so V(4) is Debug.
+func vLevel(v int) slog.Level {
+	return slog.LevelInfo - slog.Level(v)
 }
@@ -34,7 +34,7 @@ func BenchmarkUnescapeMultiline(b *testing.B) {
 
 func BenchmarkFullColorHandlerSingle(b *testing.B) {
 	opts := &slog.HandlerOptions{Level: slog.LevelInfo}
-	h := newColorTextHandler(bytes.NewBuffer(nil), opts)
+	h := newColorTextHandler(bytes.NewBuffer(nil), opts, false)
 	rec := slog.NewRecord(testTime(), slog.LevelInfo, "hello world", 0)
 	b.ResetTimer()
 	ctx := context.Background()
@@ -45,7 +45,7 @@ func BenchmarkFullColorHandlerSingle(b *testing.B) {
 
 func BenchmarkFullColorHandlerMultiline(b *testing.B) {
 	opts := &slog.HandlerOptions{Level: slog.LevelInfo}
-	h := newColorTextHandler(bytes.NewBuffer(nil), opts)
+	h := newColorTextHandler(bytes.NewBuffer(nil), opts, false)
 	msg := prettyValue{v: map[string]int{"a": 1, "b": 2}}.String()
 	rec := slog.NewRecord(testTime(), slog.LevelInfo, msg, 0)
 	ctx := context.Background()
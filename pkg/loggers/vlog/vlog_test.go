@@ -4,8 +4,14 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+
+	"github.com/vitistack/common/pkg/loggers"
+	"github.com/vitistack/common/pkg/redact"
 )
 
+// The baseline accessor must keep returning the generic interface.
+var _ loggers.Logger = Logger()
+
 func TestUnescapeMultilineAttrs(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -107,3 +113,28 @@ value extra=value`
 		t.Errorf("rebuildMultilineValue() =\n%s\nwant:\n%s", string(result), expected)
 	}
 }
+
+func TestRedaction(t *testing.T) {
+	type creds struct {
+		Endpoint  string
+		SecretKey string
+	}
+	pretty := Pretty(creds{Endpoint: "s3.local", SecretKey: "s3cr3t"}).(prettyValue).String()
+	if strings.Contains(pretty, "s3cr3t") || !strings.Contains(pretty, "s3.local") {
+		t.Errorf("Pretty() = %s, want SecretKey redacted", pretty)
+	}
+	if s := Pretty(`{"token": "abc", "user": "u"}`).(prettyValue).String(); strings.Contains(s, "abc") {
+		t.Errorf("Pretty(json string) = %s, want token redacted", s)
+	}
+
+	kv := convertKVs([]any{"password", "hunter2", "config", creds{SecretKey: "s3cr3t"}, "user", "u"})
+	if kv[1] != redact.Redacted {
+		t.Errorf("password value = %v, want redacted", kv[1])
+	}
+	if s, _ := kv[3].(string); strings.Contains(s, "s3cr3t") {
+		t.Errorf("struct value = %v, want SecretKey redacted", kv[3])
+	}
+	if kv[5] != "u" {
+		t.Errorf("user value = %v", kv[5])
+	}
+}
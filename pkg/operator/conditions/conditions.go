@@ -36,17 +36,7 @@ func SetOrUpdateCondition(conds *[]metav1.Condition, cond *metav1.Condition) boo
 	v := *cond // work on a local copy
 	for i := range s {
 		if s[i].Type == v.Type {
-			// Existing condition: check transition
-			if s[i].Status != v.Status {
-				v.LastTransitionTime = now
-			} else {
-				// Preserve prior transition time
-				v.LastTransitionTime = s[i].LastTransitionTime
-			}
-			// Always carry ObservedGeneration from input when non-zero; otherwise keep old
-			if v.ObservedGeneration == 0 {
-				v.ObservedGeneration = s[i].ObservedGeneration
-			}
+			merge(&s[i], &v, now)
 			// Replace entry
 			s[i] = v
 			*conds = s
@@ -62,6 +52,21 @@ func SetOrUpdateCondition(conds *[]metav1.Condition, cond *metav1.Condition) boo
 	return true
 }
 
+// merge applies the transition rules of SetOrUpdateCondition to v, given the existing entry.
+func merge(existing, v *metav1.Condition, now metav1.Time) {
+	// Existing condition: check transition
+	if existing.Status != v.Status {
+		v.LastTransitionTime = now
+	} else {
+		// Preserve prior transition time
+		v.LastTransitionTime = existing.LastTransitionTime
+	}
+	// Always carry ObservedGeneration from input when non-zero; otherwise keep old
+	if v.ObservedGeneration == 0 {
+		v.ObservedGeneration = existing.ObservedGeneration
+	}
+}
+
 // Remove removes a condition by type. Returns true when removed.
 func Remove(conds *[]metav1.Condition, t string) bool {
 	if conds == nil {
@@ -89,3 +94,27 @@ func New(t string, status metav1.ConditionStatus, reason, message string, observ
 		LastTransitionTime: metav1.NewTime(time.Now()),
 	}
 }
+
+// IsStale returns true when the condition was computed for an older generation than the
+// given metadata.generation. A nil condition is stale; an ObservedGeneration of 0 (not recorded)
+// is stale for any generation above 0.
+func IsStale(cond *metav1.Condition, generation int64) bool {
+	return cond == nil || cond.ObservedGeneration < generation
+}
+
+// IsCurrent returns true when the condition of the given type exists and is not stale.
+func IsCurrent(conds []metav1.Condition, t string, generation int64) bool {
+	c, ok := Get(conds, t)
+	return ok && !IsStale(&c, generation)
+}
+
+// Stale returns the types of all conditions that are stale for the given generation.
+func Stale(conds []metav1.Condition, generation int64) []string {
+	var out []string
+	for i := range conds {
+		if IsStale(&conds[i], generation) {
+			out = append(out, conds[i].Type)
+		}
+	}
+	return out
+}
@@ -1,9 +1,12 @@
 package conditions
 
 import (
+	"slices"
 	"testing"
 
+	"github.com/vitistack/common/pkg/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 )
 
 func TestSetOrUpdateCondition(t *testing.T) {
@@ -45,3 +48,157 @@ func TestSetOrUpdateCondition(t *testing.T) {
 		t.Fatalf("transition time should change when status changed")
 	}
 }
+
+func TestStaleness(t *testing.T) {
+	conds := []metav1.Condition{
+		New("Ready", metav1.ConditionTrue, "Ok", "", 2),
+		New("Synced", metav1.ConditionTrue, "Ok", "", 3),
+		New("Legacy", metav1.ConditionTrue, "Ok", "", 0),
+	}
+	tests := []struct {
+		name       string
+		generation int64
+		stale      []string
+		current    []string
+	}{
+		{name: "generation 0", generation: 0, current: []string{"Ready", "Synced", "Legacy"}},
+		{name: "generation 2", generation: 2, stale: []string{"Legacy"}, current: []string{"Ready", "Synced"}},
+		{name: "generation 3", generation: 3, stale: []string{"Ready", "Legacy"}, current: []string{"Synced"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Stale(conds, tt.generation); !slices.Equal(got, tt.stale) {
+				t.Errorf("Stale() = %v, want %v", got, tt.stale)
+			}
+			for _, c := range tt.current {
+				if !IsCurrent(conds, c, tt.generation) {
+					t.Errorf("IsCurrent(%s) = false, want true", c)
+				}
+			}
+			if IsCurrent(conds, "Missing", tt.generation) {
+				t.Errorf("IsCurrent(Missing) = true, want false")
+			}
+		})
+	}
+	if !IsStale(nil, 0) {
+		t.Errorf("IsStale(nil) = false, want true")
+	}
+}
+
+func TestSetOrUpdateTypedMachineCondition(t *testing.T) {
+	var conds []v1alpha1.MachineCondition
+	c0 := NewTyped[v1alpha1.MachineCondition](v1alpha1.MachineConditionNetworkReady, metav1.ConditionFalse, "Init", "starting")
+	if !SetOrUpdateTyped(&conds, &c0) || len(conds) != 1 {
+		t.Fatalf("expected add")
+	}
+	first := conds[0]
+	c1 := v1alpha1.MachineCondition{Type: v1alpha1.MachineConditionNetworkReady, Status: v1alpha1.ConditionFalse, Reason: "StillInit"}
+	SetOrUpdateTyped(&conds, &c1)
+	if !conds[0].LastTransitionTime.Equal(&first.LastTransitionTime) {
+		t.Fatalf("transition time should be preserved when status unchanged")
+	}
+	if conds[0].Reason != "StillInit" {
+		t.Fatalf("reason should be replaced, got %q", conds[0].Reason)
+	}
+	if _, ok := GetTyped(conds, v1alpha1.MachineConditionNetworkReady); !ok {
+		t.Fatalf("expected condition to be found")
+	}
+	if !RemoveTyped(&conds, v1alpha1.MachineConditionNetworkReady) || HasTyped(conds, v1alpha1.MachineConditionNetworkReady) {
+		t.Fatalf("expected condition to be removed")
+	}
+}
+
+func TestSetOrUpdateTypedKubernetesClusterCondition(t *testing.T) {
+	const old = "2020-01-01T00:00:00Z"
+	conds := []v1alpha1.KubernetesClusterCondition{
+		{Type: "ClusterReady", Status: v1alpha1.ConditionFalse, LastTransitionTime: old},
+	}
+	c1 := v1alpha1.KubernetesClusterCondition{Type: "ClusterReady", Status: v1alpha1.ConditionFalse, Reason: "Waiting"}
+	SetOrUpdateTyped(&conds, &c1)
+	if conds[0].LastTransitionTime != old {
+		t.Fatalf("transition time should be preserved when status unchanged, got %q", conds[0].LastTransitionTime)
+	}
+	c2 := v1alpha1.KubernetesClusterCondition{Type: "ClusterReady", Status: v1alpha1.ConditionTrue, Reason: "Ok"}
+	SetOrUpdateTyped(&conds, &c2)
+	if conds[0].LastTransitionTime == old || conds[0].LastTransitionTime == "" {
+		t.Fatalf("transition time should change when status changed, got %q", conds[0].LastTransitionTime)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	s := &Summarizer{
+		Type: v1alpha1.MachineConditionReady,
+		Dependencies: []Dependency{
+			{Type: v1alpha1.MachineConditionInfrastructureReady},
+			{Type: v1alpha1.MachineConditionNetworkReady},
+			{Type: "Degraded", Polarity: NegativePolarity, Optional: true},
+		},
+	}
+
+	conds := []metav1.Condition{
+		New(v1alpha1.MachineConditionInfrastructureReady, metav1.ConditionTrue, "Provisioned", "", 1),
+		New(v1alpha1.MachineConditionNetworkReady, metav1.ConditionTrue, "Attached", "", 1),
+	}
+	if got := s.Summarize(conds); got.Status != metav1.ConditionTrue || got.Reason != DefaultReadyReason {
+		t.Fatalf("expected ready summary, got %+v", got)
+	}
+
+	// Negative polarity: Degraded=True makes the summary False.
+	conds = append(conds, New("Degraded", metav1.ConditionTrue, "DiskPressure", "disk almost full", 1))
+	got := s.Summarize(conds)
+	if got.Status != metav1.ConditionFalse || got.Reason != "DiskPressure" || got.Message != "Degraded: disk almost full" {
+		t.Fatalf("unexpected summary for degraded: %+v", got)
+	}
+
+	// Priority: InfrastructureReady is listed first and wins over Degraded.
+	conds[0].Status = metav1.ConditionFalse
+	conds[0].Reason = "VMCreateFailed"
+	got = s.Summarize(conds)
+	if got.Reason != "VMCreateFailed" || got.Message != "InfrastructureReady is False; also affected: Degraded" {
+		t.Fatalf("unexpected summary for priority: %+v", got)
+	}
+}
+
+func TestSummarizeTypedMissingDependency(t *testing.T) {
+	s := &Summarizer{
+		Type:         v1alpha1.MachineConditionReady,
+		Dependencies: []Dependency{{Type: v1alpha1.MachineConditionBootstrapReady}},
+	}
+	got := SummarizeTyped(s, []v1alpha1.MachineCondition{})
+	if got.Status != v1alpha1.ConditionUnknown || got.Reason != "BootstrapReadyMissing" {
+		t.Fatalf("unexpected summary for missing dependency: %+v", got)
+	}
+}
+
+func TestEventRecorderRecordsTransitions(t *testing.T) {
+	fake := record.NewFakeRecorder(10)
+	ev := NewEventRecorder(fake)
+	obj := &v1alpha1.NetworkConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "nc", Namespace: "default", UID: "uid-1"}}
+
+	c := New("Ready", metav1.ConditionFalse, "Pending", "waiting for vlan", 1)
+	ev.SetOrUpdateCondition(obj, &obj.Status.Conditions, &c)
+	// Same status again: no event.
+	ev.SetOrUpdateCondition(obj, &obj.Status.Conditions, &c)
+	c2 := New("Ready", metav1.ConditionTrue, "Configured", "", 1)
+	ev.SetOrUpdateCondition(obj, &obj.Status.Conditions, &c2)
+
+	want := []string{
+		"Warning Pending Condition Ready changed to False: waiting for vlan",
+		"Normal Configured Condition Ready changed to True",
+	}
+	for _, w := range want {
+		select {
+		case got := <-fake.Events:
+			if got != w {
+				t.Fatalf("event = %q, want %q", got, w)
+			}
+		default:
+			t.Fatalf("expected event %q", w)
+		}
+	}
+	select {
+	case got := <-fake.Events:
+		t.Fatalf("unexpected event %q", got)
+	default:
+	}
+}
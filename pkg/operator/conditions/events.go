@@ -0,0 +1,131 @@
+package conditions
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// EventRecorder records a Kubernetes Event on the owning object whenever a condition changes
+// Status through its SetOrUpdate* methods. The event type is Normal when the new status is the
+// healthy one (True, or False for types registered as negative polarity) and Warning otherwise.
+// Identical events for the same object and condition type are only recorded once in a row.
+//
+// Example:
+//
+//	ev := conditions.NewEventRecorder(mgr.GetEventRecorderFor("networkconfiguration-controller"))
+//	ev.SetOrUpdateCondition(nc, &nc.Status.Conditions, &cond)
+type EventRecorder struct {
+	recorder record.EventRecorder
+	negative map[string]struct{}
+
+	mu   sync.Mutex
+	last map[string]string
+}
+
+// NewEventRecorder wraps r. negativeTypes lists condition types that are bad when True,
+// such as "Degraded"; transitions of those to True are recorded as Warning.
+func NewEventRecorder(r record.EventRecorder, negativeTypes ...string) *EventRecorder {
+	neg := make(map[string]struct{}, len(negativeTypes))
+	for _, t := range negativeTypes {
+		neg[t] = struct{}{}
+	}
+	return &EventRecorder{recorder: r, negative: neg, last: map[string]string{}}
+}
+
+// SetOrUpdateCondition behaves like the package-level SetOrUpdateCondition and records an
+// Event on obj when the condition is added or its Status changes.
+func (e *EventRecorder) SetOrUpdateCondition(obj runtime.Object, conds *[]metav1.Condition, cond *metav1.Condition) bool {
+	if conds == nil || cond == nil {
+		return false
+	}
+	prev, found := Get(*conds, cond.Type)
+	updated := SetOrUpdateCondition(conds, cond)
+	if updated && (!found || prev.Status != cond.Status) {
+		e.record(obj, cond)
+	}
+	return updated
+}
+
+// SetOrUpdateTypedWithEvents is EventRecorder.SetOrUpdateCondition for the project's own
+// condition types. It is a function rather than a method because methods cannot take type parameters.
+func SetOrUpdateTypedWithEvents[T any, P Convertible[T]](e *EventRecorder, obj runtime.Object, conds *[]T, cond *T) bool {
+	if conds == nil || cond == nil {
+		return false
+	}
+	prev, found := GetTyped[T, P](*conds, P(cond).ToCondition().Type)
+	updated := SetOrUpdateTyped[T, P](conds, cond)
+	next := P(cond).ToCondition()
+	if updated && (!found || P(&prev).ToCondition().Status != next.Status) {
+		e.record(obj, &next)
+	}
+	return updated
+}
+
+// Forget drops the deduplication state for obj. Call it once the object is deleted.
+func (e *EventRecorder) Forget(obj runtime.Object) {
+	prefix := objectKey(obj) + "/"
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for k := range e.last {
+		if strings.HasPrefix(k, prefix) {
+			delete(e.last, k)
+		}
+	}
+}
+
+func (e *EventRecorder) record(obj runtime.Object, cond *metav1.Condition) {
+	if e == nil || e.recorder == nil || obj == nil {
+		return
+	}
+	eventType := e.eventType(cond)
+	reason := cond.Reason
+	if reason == "" {
+		reason = cond.Type
+	}
+	message := fmt.Sprintf("Condition %s changed to %s", cond.Type, cond.Status)
+	if cond.Message != "" {
+		message += ": " + cond.Message
+	}
+
+	key := objectKey(obj) + "/" + cond.Type
+	fingerprint := eventType + "|" + reason + "|" + message
+	e.mu.Lock()
+	if e.last[key] == fingerprint {
+		e.mu.Unlock()
+		return
+	}
+	e.last[key] = fingerprint
+	e.mu.Unlock()
+
+	e.recorder.Event(obj, eventType, reason, message)
+}
+
+func (e *EventRecorder) eventType(cond *metav1.Condition) string {
+	good := metav1.ConditionTrue
+	if _, ok := e.negative[cond.Type]; ok {
+		good = metav1.ConditionFalse
+	}
+	if cond.Status == good {
+		return corev1.EventTypeNormal
+	}
+	return corev1.EventTypeWarning
+}
+
+// objectKey identifies obj by UID, falling back to namespace/name for objects without one.
+func objectKey(obj runtime.Object) string {
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return fmt.Sprintf("%p", obj)
+	}
+	if uid := m.GetUID(); uid != "" {
+		return string(uid)
+	}
+	return m.GetNamespace() + "/" + m.GetName()
+}
@@ -0,0 +1,179 @@
+package conditions
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Polarity tells the summarizer which status of a sub-condition is the healthy one.
+type Polarity int
+
+const (
+	// PositivePolarity means True is good, e.g. NetworkReady or BootstrapReady.
+	PositivePolarity Polarity = iota
+	// NegativePolarity means True is bad, e.g. Degraded or Stalled.
+	NegativePolarity
+)
+
+// Default reason used for the summary condition when every dependency is healthy.
+const DefaultReadyReason = "AllDependenciesReady"
+
+// Dependency is a sub-condition that contributes to a summary condition.
+type Dependency struct {
+	Type     string
+	Polarity Polarity
+	// Optional marks a dependency whose absence does not affect the summary.
+	Optional bool
+}
+
+// Summarizer derives a top-level condition (typically "Ready") from a list of sub-conditions.
+// Dependencies are listed in priority order: when several are unhealthy, the first one listed
+// supplies the Reason and leads the Message.
+//
+// Example:
+//
+//	s := &conditions.Summarizer{
+//		Type: v1alpha1.MachineConditionReady,
+//		Dependencies: []conditions.Dependency{
+//			{Type: v1alpha1.MachineConditionInfrastructureReady},
+//			{Type: v1alpha1.MachineConditionNetworkReady},
+//			{Type: v1alpha1.MachineConditionBootstrapReady},
+//		},
+//	}
+//	ready := conditions.SummarizeTyped(s, machine.Status.Conditions)
+//	conditions.SetOrUpdateTyped(&machine.Status.Conditions, &ready)
+type Summarizer struct {
+	// Type of the summary condition, e.g. "Ready".
+	Type string
+	// Dependencies in priority order.
+	Dependencies []Dependency
+	// ReadyReason and ReadyMessage are used when all dependencies are healthy.
+	// ReadyReason defaults to DefaultReadyReason.
+	ReadyReason  string
+	ReadyMessage string
+	// ObservedGeneration is copied to the summary condition.
+	ObservedGeneration int64
+}
+
+// severity of a sub-condition; higher is worse.
+const (
+	severityOK = iota
+	severityUnknown
+	severityBad
+)
+
+type contribution struct {
+	dep      Dependency
+	cond     metav1.Condition
+	found    bool
+	severity int
+}
+
+// Summarize computes the summary condition from conds. The returned condition has a zero
+// LastTransitionTime so SetOrUpdateCondition applies the usual transition rules.
+func (s *Summarizer) Summarize(conds []metav1.Condition) metav1.Condition {
+	var worst []contribution
+	for _, dep := range s.Dependencies {
+		c, found := Get(conds, dep.Type)
+		if !found && dep.Optional {
+			continue
+		}
+		ct := contribution{dep: dep, cond: c, found: found, severity: severityOf(dep, &c, found)}
+		if ct.severity == severityOK {
+			continue
+		}
+		if len(worst) > 0 && ct.severity < worst[0].severity {
+			continue
+		}
+		if len(worst) > 0 && ct.severity > worst[0].severity {
+			worst = worst[:0]
+		}
+		worst = append(worst, ct)
+	}
+
+	out := metav1.Condition{Type: s.Type, ObservedGeneration: s.ObservedGeneration}
+	if len(worst) == 0 {
+		out.Status = metav1.ConditionTrue
+		out.Reason = s.ReadyReason
+		if out.Reason == "" {
+			out.Reason = DefaultReadyReason
+		}
+		out.Message = s.ReadyMessage
+		return out
+	}
+
+	first := &worst[0]
+	out.Status = metav1.ConditionFalse
+	if first.severity == severityUnknown {
+		out.Status = metav1.ConditionUnknown
+	}
+	out.Reason = first.reason()
+	out.Message = first.message()
+	if len(worst) > 1 {
+		rest := make([]string, 0, len(worst)-1)
+		for i := 1; i < len(worst); i++ {
+			rest = append(rest, worst[i].dep.Type)
+		}
+		out.Message = fmt.Sprintf("%s; also affected: %s", out.Message, strings.Join(rest, ", "))
+	}
+	return out
+}
+
+// SummarizeTyped is Summarize for the project's own condition types.
+func SummarizeTyped[T any, P Convertible[T]](s *Summarizer, conds []T) T {
+	converted := make([]metav1.Condition, 0, len(conds))
+	for i := range conds {
+		converted = append(converted, P(&conds[i]).ToCondition())
+	}
+	sum := s.Summarize(converted)
+	var out T
+	P(&out).FromCondition(sum)
+	return out
+}
+
+func severityOf(dep Dependency, c *metav1.Condition, found bool) int {
+	if !found {
+		return severityUnknown
+	}
+	good, bad := metav1.ConditionTrue, metav1.ConditionFalse
+	if dep.Polarity == NegativePolarity {
+		good, bad = bad, good
+	}
+	switch c.Status {
+	case good:
+		return severityOK
+	case bad:
+		return severityBad
+	default:
+		return severityUnknown
+	}
+}
+
+func (ct *contribution) reason() string {
+	if !ct.found {
+		return ct.dep.Type + "Missing"
+	}
+	if ct.cond.Reason != "" {
+		return ct.cond.Reason
+	}
+	if ct.severity == severityUnknown {
+		return ct.dep.Type + "Unknown"
+	}
+	if ct.dep.Polarity == NegativePolarity {
+		return ct.dep.Type
+	}
+	return "Not" + ct.dep.Type
+}
+
+func (ct *contribution) message() string {
+	switch {
+	case !ct.found:
+		return fmt.Sprintf("%s: condition not reported yet", ct.dep.Type)
+	case ct.cond.Message != "":
+		return fmt.Sprintf("%s: %s", ct.dep.Type, ct.cond.Message)
+	default:
+		return fmt.Sprintf("%s is %s", ct.dep.Type, ct.cond.Status)
+	}
+}
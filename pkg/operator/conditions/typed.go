@@ -0,0 +1,87 @@
+package conditions
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Convertible is satisfied by pointers to condition structs that can be converted to and
+// from metav1.Condition, such as *v1alpha1.MachineCondition, *v1alpha1.ProviderCondition,
+// *v1alpha1.KubernetesClusterCondition, *v1alpha1.KubernetesProviderCondition and
+// *v1alpha1.EtcdBackupCondition. It lets the *Typed helpers share the transition rules
+// of the metav1.Condition helpers.
+type Convertible[T any] interface {
+	*T
+	ToCondition() metav1.Condition
+	FromCondition(metav1.Condition)
+}
+
+// GetTyped returns the condition with the given type, if present.
+func GetTyped[T any, P Convertible[T]](conds []T, t string) (T, bool) {
+	for i := range conds {
+		if P(&conds[i]).ToCondition().Type == t {
+			return conds[i], true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// HasTyped returns true if a condition of the given type exists.
+func HasTyped[T any, P Convertible[T]](conds []T, t string) bool {
+	_, ok := GetTyped[T, P](conds, t)
+	return ok
+}
+
+// SetOrUpdateTyped inserts or updates a condition in-place using the same rules as
+// SetOrUpdateCondition. Returns true when the slice was modified.
+func SetOrUpdateTyped[T any, P Convertible[T]](conds *[]T, cond *T) bool {
+	if conds == nil || cond == nil {
+		return false
+	}
+	now := metav1.NewTime(time.Now())
+	s := *conds
+	v := P(cond).ToCondition()
+	for i := range s {
+		existing := P(&s[i]).ToCondition()
+		if existing.Type == v.Type {
+			merge(&existing, &v, now)
+			P(&s[i]).FromCondition(v)
+			*conds = s
+			return true
+		}
+	}
+	// Not found: set transition time and append
+	if v.LastTransitionTime.IsZero() {
+		v.LastTransitionTime = now
+	}
+	var out T
+	P(&out).FromCondition(v)
+	s = append(s, out)
+	*conds = s
+	return true
+}
+
+// RemoveTyped removes a condition by type. Returns true when removed.
+func RemoveTyped[T any, P Convertible[T]](conds *[]T, t string) bool {
+	if conds == nil {
+		return false
+	}
+	s := *conds
+	for i := range s {
+		if P(&s[i]).ToCondition().Type == t {
+			s = append(s[:i], s[i+1:]...)
+			*conds = s
+			return true
+		}
+	}
+	return false
+}
+
+// NewTyped creates a new condition of type T with transition time set to now.
+func NewTyped[T any, P Convertible[T]](t string, status metav1.ConditionStatus, reason, message string) T {
+	var out T
+	P(&out).FromCondition(New(t, status, reason, message, 0))
+	return out
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/vitistack/common/pkg/clients/k8sclient"
 	"github.com/vitistack/common/pkg/loggers/vlog"
@@ -27,10 +28,51 @@ func (r Ref) String() string { return fmt.Sprintf("%s/%s %s", r.Group, r.Version
 // EnsureInstalled checks that all provided CRD resources exist via the Discovery API.
 // It returns an error listing any that are missing.
 func EnsureInstalled(ctx context.Context, dc discovery.DiscoveryInterface, crds []Ref) error {
-	if len(crds) == 0 {
-		return nil
+	missing, err := findMissing(dc, crds)
+	if err != nil {
+		return err
 	}
+	logChanges(ctx, "installed:"+refsKey(crds), missing, func(log *vlog.SlogLogger, m string) {
+		log.Warn("Required CRD/resource not found", "resource", m)
+	}, "Required CRDs/resources are installed")
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required CRDs/resources: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
 
+// logged holds the problems last logged per check, so that repeated checks such as readiness
+// probes only log when the problems change.
+var logged sync.Map
+
+// logChanges logs each problem with warn when the set differs from the last one logged for key,
+// and resolved once the problems are gone.
+func logChanges(ctx context.Context, key string, problems []string, warn func(*vlog.SlogLogger, string), resolved string) {
+	state := strings.Join(problems, "\n")
+	prev, seen := logged.Swap(key, state)
+	if (seen && prev == state) || (!seen && state == "") {
+		return
+	}
+	log := vlog.FromContext(ctx)
+	if state == "" {
+		log.Info(resolved)
+		return
+	}
+	for _, p := range problems {
+		warn(log, p)
+	}
+}
+
+func refsKey(crds []Ref) string {
+	keys := make([]string, 0, len(crds))
+	for _, r := range crds {
+		keys = append(keys, r.String())
+	}
+	return strings.Join(keys, ",")
+}
+
+// findMissing returns the refs that are not served by the API server.
+func findMissing(dc discovery.DiscoveryInterface, crds []Ref) ([]string, error) {
 	var missing []string
 	for _, ref := range crds {
 		gv := fmt.Sprintf("%s/%s", ref.Group, ref.Version)
@@ -39,12 +81,11 @@ func EnsureInstalled(ctx context.Context, dc discovery.DiscoveryInterface, crds
 		if err != nil {
 			// NotFound means the entire GV isn't served; consider missing.
 			if errors.IsNotFound(err) {
-				vlog.Warnf("Required API group/version not found: %s (for resource %s)", gv, ref.Resource)
 				missing = append(missing, ref.String())
 				continue
 			}
 			// Any other error (e.g., permissions, connectivity) is treated as fatal for certainty.
-			return fmt.Errorf("failed to discover resources for %s: %w", gv, err)
+			return nil, fmt.Errorf("failed to discover resources for %s: %w", gv, err)
 		}
 
 		// Scan for the specific plural resource name.
@@ -60,15 +101,12 @@ func EnsureInstalled(ctx context.Context, dc discovery.DiscoveryInterface, crds
 			missing = append(missing, ref.String())
 		}
 	}
-
-	if len(missing) > 0 {
-		return fmt.Errorf("missing required CRDs/resources: %s", strings.Join(missing, ", "))
-	}
-	return nil
+	return missing, nil
 }
 
 // MustEnsureInstalled checks the provided CRDs using the global DiscoveryClient
-// and panics if any are missing. Suitable to call during operator startup.
+// and panics if any are missing. Suitable to call during operator startup; prefer
+// WaitForInstalled when CRDs may be installed together with the operator.
 func MustEnsureInstalled(ctx context.Context, crds ...Ref) {
 	if k8sclient.DiscoveryClient == nil {
 		vlog.Error("Discovery client is not initialized; call k8sclient.Init() first")
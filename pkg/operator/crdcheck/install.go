@@ -0,0 +1,173 @@
+package crdcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vitistack/common/crds"
+	"github.com/vitistack/common/pkg/loggers/vlog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/dynamic"
+)
+
+// DefaultFieldManager is the server-side apply field manager used by Installer.
+const DefaultFieldManager = "vitistack-crd-installer"
+
+// ErrDowngrade is returned when the installed CRD serves a newer version than the manifest, or
+// when applying the manifest would remove fields from the schema of an installed version.
+var ErrDowngrade = errors.New("refusing to downgrade CRD")
+
+// Installer server-side applies CRD manifests and waits for them to become Established.
+type Installer struct {
+	Client dynamic.Interface
+	// FieldManager owns the applied fields (default DefaultFieldManager).
+	FieldManager string
+	// Timeout bounds the wait for each CRD to become Established (default 1m).
+	Timeout time.Duration
+	// Interval between Established checks (default 1s).
+	Interval time.Duration
+	// AllowDowngrade applies manifests even when the installed CRD has a newer version or a
+	// schema with fields the manifest lacks.
+	AllowDowngrade bool
+	// Force takes ownership of fields managed by others, e.g. kubectl or Helm. Without it such
+	// fields make the apply fail with a Conflict error (see apierrors.IsConflict).
+	Force bool
+}
+
+// NewInstaller returns an Installer with default settings.
+func NewInstaller(dyn dynamic.Interface) *Installer {
+	return &Installer{Client: dyn, FieldManager: DefaultFieldManager, Timeout: time.Minute, Interval: time.Second}
+}
+
+// InstallEmbedded installs or upgrades the CRDs embedded in github.com/vitistack/common/crds
+// with the default settings, so CRDs managed by another tool fail with a conflict. Suitable for
+// operator startup or a pre-install job.
+func InstallEmbedded(ctx context.Context, dyn dynamic.Interface) error {
+	manifests, err := LoadManifests(crds.FS, "*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to load embedded CRDs: %w", err)
+	}
+	return NewInstaller(dyn).Install(ctx, manifests)
+}
+
+// Install applies each manifest in order and waits until it is Established. It stops at the
+// first error; CRDs that would be downgraded fail with ErrDowngrade unless AllowDowngrade is set,
+// and field ownership conflicts fail unless Force is set.
+func (i *Installer) Install(ctx context.Context, manifests []*unstructured.Unstructured) error {
+	for _, m := range manifests {
+		if err := i.apply(ctx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *Installer) apply(ctx context.Context, manifest *unstructured.Unstructured) error {
+	name := manifest.GetName()
+	ri := i.Client.Resource(CRDResource)
+
+	existing, err := ri.Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return fmt.Errorf("failed to get CRD %s: %w", name, err)
+	case !i.AllowDowngrade:
+		have, want := newestVersion(existing), newestVersion(manifest)
+		if version.CompareKubeAwareVersionStrings(have, want) > 0 {
+			return fmt.Errorf("%w %s: installed version %s is newer than %s", ErrDowngrade, name, have, want)
+		}
+		if removed := removedFields(existing, manifest); len(removed) > 0 {
+			return fmt.Errorf("%w %s: the manifest removes %s", ErrDowngrade, name, strings.Join(removed, ", "))
+		}
+	}
+
+	fieldManager := i.FieldManager
+	if fieldManager == "" {
+		fieldManager = DefaultFieldManager
+	}
+	obj := manifest.DeepCopy()
+	unstructured.RemoveNestedField(obj.Object, "status")
+	unstructured.RemoveNestedField(obj.Object, "metadata", "creationTimestamp")
+	if _, err := ri.Apply(ctx, name, obj, metav1.ApplyOptions{FieldManager: fieldManager, Force: i.Force}); err != nil {
+		return fmt.Errorf("failed to apply CRD %s: %w", name, err)
+	}
+	if err := i.waitEstablished(ctx, name); err != nil {
+		return err
+	}
+	vlog.FromContext(ctx).Info("CRD applied and established", "crd", name)
+	return nil
+}
+
+func (i *Installer) waitEstablished(ctx context.Context, name string) error {
+	timeout, interval := i.Timeout, i.Interval
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+	err := wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(ctx context.Context) (bool, error) {
+		crd, err := i.Client.Resource(CRDResource).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, nil //nolint:nilerr // keep polling on transient errors
+		}
+		return isEstablished(crd), nil
+	})
+	if err != nil {
+		return fmt.Errorf("CRD %s did not become Established: %w", name, err)
+	}
+	return nil
+}
+
+func isEstablished(crd *unstructured.Unstructured) bool {
+	conds, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, c := range conds {
+		m, ok := c.(map[string]any)
+		if ok && m["type"] == "Established" && m["status"] == string(metav1.ConditionTrue) {
+			return true
+		}
+	}
+	return false
+}
+
+// removedFields returns the fields, as "version: path", that the installed schema of a version
+// has and the manifest's schema of the same version lacks. Applying such a manifest would prune
+// them from stored objects even though the version name is unchanged.
+func removedFields(installed, manifest *unstructured.Unstructured) []string {
+	have := map[string]map[string]any{}
+	for _, v := range crdVersions(installed) {
+		have[v.name] = v.schema
+	}
+	var out []string
+	for _, v := range crdVersions(manifest) {
+		old, ok := have[v.name]
+		if !ok || old == nil || v.schema == nil {
+			continue
+		}
+		for _, p := range missingFields(v.schema, old, "") {
+			out = append(out, v.name+": "+p)
+		}
+	}
+	return out
+}
+
+// newestVersion returns the newest served or storage version of a CRD by Kubernetes version ordering.
+func newestVersion(crd *unstructured.Unstructured) string {
+	var newest string
+	for _, v := range crdVersions(crd) {
+		if !v.served && !v.storage {
+			continue
+		}
+		if newest == "" || version.CompareKubeAwareVersionStrings(v.name, newest) > 0 {
+			newest = v.name
+		}
+	}
+	return newest
+}
@@ -0,0 +1,140 @@
+package crdcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vitistack/common/crds"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// establishOnApply makes the fake client store applied CRDs with an Established condition,
+// as the API server would.
+func establishOnApply(t *testing.T, dyn interface {
+	PrependReactor(verb, resource string, reaction k8stesting.ReactionFunc)
+	Tracker() k8stesting.ObjectTracker
+}) *[]string {
+	t.Helper()
+	var applied []string
+	dyn.PrependReactor("patch", CRDResource.Resource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		obj := &unstructured.Unstructured{}
+		if err := json.Unmarshal(patch.GetPatch(), &obj.Object); err != nil {
+			return true, nil, err
+		}
+		_ = unstructured.SetNestedSlice(obj.Object, []any{
+			map[string]any{"type": "Established", "status": "True"},
+		}, "status", "conditions")
+		applied = append(applied, obj.GetName())
+		if _, err := dyn.Tracker().Get(CRDResource, "", obj.GetName()); err == nil {
+			return true, obj, dyn.Tracker().Update(CRDResource, obj, "")
+		}
+		return true, obj, dyn.Tracker().Create(CRDResource, obj, "")
+	})
+	return &applied
+}
+
+func TestInstallerAppliesAndWaits(t *testing.T) {
+	expected := loadExpected(t)
+	dyn := newFakeDynamic()
+	applied := establishOnApply(t, dyn)
+
+	inst := NewInstaller(dyn)
+	inst.Interval, inst.Timeout = time.Millisecond, time.Second
+	if err := inst.Install(context.Background(), expected); err != nil {
+		t.Fatalf("Install() unexpected error: %v", err)
+	}
+	if len(*applied) != 1 || (*applied)[0] != kubernetesClustersCRD {
+		t.Fatalf("applied = %v", *applied)
+	}
+	// Upgrading onto itself is allowed.
+	if err := inst.Install(context.Background(), expected); err != nil {
+		t.Fatalf("second Install() unexpected error: %v", err)
+	}
+}
+
+func TestInstallerRefusesDowngrade(t *testing.T) {
+	expected := loadExpected(t)
+	installed := expected[0].DeepCopy()
+	versions, _, _ := unstructured.NestedSlice(installed.Object, "spec", "versions")
+	newer := versions[0].(map[string]any)
+	newer = runtime.DeepCopyJSON(newer)
+	newer["name"] = "v1beta1"
+	_ = unstructured.SetNestedSlice(installed.Object, append(versions, newer), "spec", "versions")
+
+	dyn := newFakeDynamic(installed)
+	applied := establishOnApply(t, dyn)
+	err := NewInstaller(dyn).Install(context.Background(), expected)
+	if !errors.Is(err, ErrDowngrade) {
+		t.Fatalf("Install() error = %v, want ErrDowngrade", err)
+	}
+	if len(*applied) != 0 {
+		t.Fatalf("nothing should be applied on downgrade, got %v", *applied)
+	}
+}
+
+func TestInstallerRefusesSameVersionFieldRemoval(t *testing.T) {
+	installed := loadExpected(t)[0]
+	manifest := installed.DeepCopy()
+	versions, _, _ := unstructured.NestedSlice(manifest.Object, "spec", "versions")
+	unstructured.RemoveNestedField(versions[0].(map[string]any), "schema", "openAPIV3Schema", "properties", "spec",
+		"properties", "topology", "properties", "controlplane", "properties", "architecture")
+	_ = unstructured.SetNestedSlice(manifest.Object, versions, "spec", "versions")
+
+	dyn := newFakeDynamic(installed)
+	applied := establishOnApply(t, dyn)
+	inst := NewInstaller(dyn)
+	err := inst.Install(context.Background(), []*unstructured.Unstructured{manifest})
+	if !errors.Is(err, ErrDowngrade) || !strings.Contains(err.Error(), "spec.topology.controlplane.architecture") {
+		t.Fatalf("Install() error = %v, want ErrDowngrade naming the removed field", err)
+	}
+	if len(*applied) != 0 {
+		t.Fatalf("nothing should be applied, got %v", *applied)
+	}
+
+	inst.AllowDowngrade, inst.Interval = true, time.Millisecond
+	if err := inst.Install(context.Background(), []*unstructured.Unstructured{manifest}); err != nil {
+		t.Fatalf("Install() with AllowDowngrade unexpected error: %v", err)
+	}
+}
+
+func TestInstallerSurfacesConflictsUnlessForced(t *testing.T) {
+	expected := loadExpected(t)
+	dyn := newFakeDynamic()
+	applied := establishOnApply(t, dyn)
+	// Simulate fields owned by another manager, e.g. Helm.
+	dyn.PrependReactor("patch", CRDResource.Resource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+		opts := action.(k8stesting.PatchActionImpl).PatchOptions
+		if opts.Force != nil && *opts.Force {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewConflict(CRDResource.GroupResource(), kubernetesClustersCRD, errors.New("conflict with helm"))
+	})
+
+	inst := NewInstaller(dyn)
+	inst.Interval, inst.Timeout = time.Millisecond, time.Second
+	if err := inst.Install(context.Background(), expected); !apierrors.IsConflict(err) {
+		t.Fatalf("Install() error = %v, want a conflict", err)
+	}
+	inst.Force = true
+	if err := inst.Install(context.Background(), expected); err != nil {
+		t.Fatalf("Install() with Force unexpected error: %v", err)
+	}
+	if len(*applied) != 1 {
+		t.Fatalf("applied = %v", *applied)
+	}
+}
+
+func TestEmbeddedManifests(t *testing.T) {
+	manifests, err := LoadManifests(crds.FS, "*.yaml")
+	if err != nil || len(manifests) == 0 {
+		t.Fatalf("embedded manifests: %d, %v", len(manifests), err)
+	}
+}
@@ -0,0 +1,294 @@
+package crdcheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/vitistack/common/pkg/loggers/vlog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+)
+
+// CRDResource is the GroupVersionResource of CustomResourceDefinitions.
+var CRDResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// ParseManifests decodes all CustomResourceDefinition documents in a (multi-document) YAML or JSON stream.
+func ParseManifests(data []byte) ([]*unstructured.Unstructured, error) {
+	dec := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	var out []*unstructured.Unstructured
+	for {
+		var obj map[string]any
+		if err := dec.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				return out, nil
+			}
+			return nil, err
+		}
+		if len(obj) == 0 {
+			continue
+		}
+		u := &unstructured.Unstructured{Object: obj}
+		if u.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+		out = append(out, u)
+	}
+}
+
+// LoadManifests reads and parses every file in fsys matching pattern, e.g.
+//
+//	crdcheck.LoadManifests(os.DirFS("crds"), "*.yaml")
+func LoadManifests(fsys fs.FS, pattern string) ([]*unstructured.Unstructured, error) {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	var out []*unstructured.Unstructured
+	for _, f := range files {
+		data, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			return nil, err
+		}
+		crds, err := ParseManifests(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", f, err)
+		}
+		out = append(out, crds...)
+	}
+	return out, nil
+}
+
+// Drift is a single incompatibility between an installed CRD and its expected manifest.
+type Drift struct {
+	CRD     string
+	Kind    string
+	Version string
+	Message string
+}
+
+func (d Drift) String() string { return d.Message }
+
+// Report is the result of a compatibility check.
+type Report struct {
+	Drift []Drift
+}
+
+// Compatible returns true when no drift was found.
+func (r *Report) Compatible() bool { return len(r.Drift) == 0 }
+
+// Err returns an error listing all drift, or nil when compatible.
+func (r *Report) Err() error {
+	if r.Compatible() {
+		return nil
+	}
+	msgs := make([]string, 0, len(r.Drift))
+	for _, d := range r.Drift {
+		msgs = append(msgs, d.Message)
+	}
+	return fmt.Errorf("installed CRDs are incompatible: %s", strings.Join(msgs, "; "))
+}
+
+// CompatibilityOptions tunes CheckCompatibility.
+type CompatibilityOptions struct {
+	// RequiredFields lists, per Kind, the field paths the operator relies on, e.g.
+	// {"KubernetesCluster": {"spec.topology.controlplane.architecture"}}. Array items are
+	// addressed with "[]", e.g. "spec.topology.workers.nodePools[].name".
+	// Kinds without an entry are checked against every field in their manifest.
+	RequiredFields map[string][]string
+}
+
+// CheckCompatibility compares the installed CustomResourceDefinitions with the expected manifests.
+// It checks served and storage versions and that the fields the operator relies on exist in the
+// installed OpenAPI schema. A missing CRD is reported as drift; API errors are returned.
+func CheckCompatibility(ctx context.Context, dyn dynamic.Interface, expected []*unstructured.Unstructured, opts CompatibilityOptions) (*Report, error) {
+	report := &Report{}
+	for _, want := range expected {
+		kind, _, _ := unstructured.NestedString(want.Object, "spec", "names", "kind")
+		installed, err := dyn.Resource(CRDResource).Get(ctx, want.GetName(), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			report.Drift = append(report.Drift, Drift{CRD: want.GetName(), Kind: kind,
+				Message: fmt.Sprintf("%s CRD %s is not installed", kind, want.GetName())})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get CRD %s: %w", want.GetName(), err)
+		}
+		report.Drift = append(report.Drift, Compare(installed, want, opts.RequiredFields[kind])...)
+	}
+	return report, nil
+}
+
+// EnsureCompatible runs CheckCompatibility and returns an error listing the drift, if any. Each
+// drift is logged through the logger in ctx, but only when it differs from the previous call for
+// the same CRDs, so EnsureCompatible can back a readiness probe.
+func EnsureCompatible(ctx context.Context, dyn dynamic.Interface, expected []*unstructured.Unstructured, opts CompatibilityOptions) error {
+	report, err := CheckCompatibility(ctx, dyn, expected, opts)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(expected))
+	for _, e := range expected {
+		names = append(names, e.GetName())
+	}
+	msgs := make([]string, 0, len(report.Drift))
+	for _, d := range report.Drift {
+		msgs = append(msgs, d.Message)
+	}
+	logChanges(ctx, "compatible:"+strings.Join(names, ","), msgs, func(log *vlog.SlogLogger, m string) {
+		log.Warn("CRD drift", "drift", m)
+	}, "Installed CRDs are compatible again")
+	return report.Err()
+}
+
+type crdVersion struct {
+	name    string
+	served  bool
+	storage bool
+	schema  map[string]any
+}
+
+func crdVersions(crd *unstructured.Unstructured) []crdVersion {
+	raw, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	out := make([]crdVersion, 0, len(raw))
+	for _, r := range raw {
+		m, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		v := crdVersion{}
+		v.name, _, _ = unstructured.NestedString(m, "name")
+		v.served, _, _ = unstructured.NestedBool(m, "served")
+		v.storage, _, _ = unstructured.NestedBool(m, "storage")
+		v.schema, _, _ = unstructured.NestedMap(m, "schema", "openAPIV3Schema")
+		out = append(out, v)
+	}
+	return out
+}
+
+// Compare returns the drift between an installed CRD and the expected manifest. When required is
+// empty every field of the expected schema must exist in the installed schema.
+func Compare(installed, expected *unstructured.Unstructured, required []string) []Drift {
+	kind, _, _ := unstructured.NestedString(expected.Object, "spec", "names", "kind")
+	name := expected.GetName()
+	drift := func(version, format string, args ...any) Drift {
+		return Drift{CRD: name, Kind: kind, Version: version,
+			Message: fmt.Sprintf("installed %s CRD ", kind) + fmt.Sprintf(format, args...)}
+	}
+
+	have := map[string]crdVersion{}
+	haveStorage := "<none>"
+	for _, v := range crdVersions(installed) {
+		have[v.name] = v
+		if v.storage {
+			haveStorage = v.name
+		}
+	}
+
+	var out []Drift
+	for _, want := range crdVersions(expected) {
+		if want.storage && want.name != haveStorage {
+			out = append(out, drift(want.name, "stores %s, expected %s", haveStorage, want.name))
+		}
+		if !want.served {
+			continue
+		}
+		got, ok := have[want.name]
+		if !ok || !got.served {
+			out = append(out, drift(want.name, "does not serve %s", want.name))
+			continue
+		}
+		if got.schema == nil {
+			// No schema means nothing is pruned.
+			continue
+		}
+		missing := required
+		if len(missing) == 0 {
+			missing = missingFields(got.schema, want.schema, "")
+		} else {
+			missing = slices.DeleteFunc(slices.Clone(missing), func(p string) bool { return hasField(got.schema, p) })
+		}
+		for _, p := range missing {
+			out = append(out, drift(want.name, "lacks %s (%s)", p, want.name))
+		}
+	}
+	return out
+}
+
+// missingFields walks the expected schema and returns the top-most paths absent from installed.
+func missingFields(installed, expected map[string]any, prefix string) []string {
+	if preservesUnknown(installed) {
+		return nil
+	}
+	var out []string
+	if props, ok := expected["properties"].(map[string]any); ok {
+		haveProps, _ := installed["properties"].(map[string]any)
+		keys := make([]string, 0, len(props))
+		for k := range props {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			path := joinPath(prefix, k)
+			have, ok := haveProps[k].(map[string]any)
+			if !ok {
+				out = append(out, path)
+				continue
+			}
+			want, _ := props[k].(map[string]any)
+			out = append(out, missingFields(have, want, path)...)
+		}
+	}
+	if items, ok := expected["items"].(map[string]any); ok {
+		if have, ok := installed["items"].(map[string]any); ok {
+			out = append(out, missingFields(have, items, prefix+"[]")...)
+		} else {
+			out = append(out, prefix+"[]")
+		}
+	}
+	return out
+}
+
+// hasField reports whether a dotted field path exists in the schema.
+func hasField(s map[string]any, path string) bool {
+	for _, seg := range strings.Split(path, ".") {
+		if preservesUnknown(s) {
+			return true
+		}
+		name, isArray := strings.CutSuffix(seg, "[]")
+		props, _ := s["properties"].(map[string]any)
+		next, ok := props[name].(map[string]any)
+		if !ok {
+			return false
+		}
+		s = next
+		if isArray {
+			if s, ok = s["items"].(map[string]any); !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func preservesUnknown(s map[string]any) bool {
+	b, _ := s["x-kubernetes-preserve-unknown-fields"].(bool)
+	return b
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
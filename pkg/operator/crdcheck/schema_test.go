@@ -0,0 +1,103 @@
+package crdcheck
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/vitistack/common/pkg/loggers/vlog"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+)
+
+const kubernetesClustersCRD = "kubernetesclusters.vitistack.io"
+
+func loadExpected(t *testing.T) []*unstructured.Unstructured {
+	t.Helper()
+	crds, err := LoadManifests(os.DirFS("../../../crds"), "vitistack.io_kubernetesclusters.yaml")
+	if err != nil || len(crds) != 1 || crds[0].GetName() != kubernetesClustersCRD {
+		t.Fatalf("LoadManifests() = %d crds, %v", len(crds), err)
+	}
+	return crds
+}
+
+func newFakeDynamic(objs ...runtime.Object) *fakedynamic.FakeDynamicClient {
+	return fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{CRDResource: "CustomResourceDefinitionList"}, objs...)
+}
+
+func TestCheckCompatibilityReportsMissingField(t *testing.T) {
+	expected := loadExpected(t)
+	installed := expected[0].DeepCopy()
+	versions, _, _ := unstructured.NestedSlice(installed.Object, "spec", "versions")
+	v0 := versions[0].(map[string]any)
+	unstructured.RemoveNestedField(v0, "schema", "openAPIV3Schema", "properties", "spec", "properties",
+		"topology", "properties", "controlplane", "properties", "architecture")
+	_ = unstructured.SetNestedSlice(installed.Object, versions, "spec", "versions")
+
+	report, err := CheckCompatibility(context.Background(), newFakeDynamic(installed), expected, CompatibilityOptions{})
+	if err != nil {
+		t.Fatalf("CheckCompatibility() unexpected error: %v", err)
+	}
+	if len(report.Drift) != 1 {
+		t.Fatalf("Drift = %v, want exactly one", report.Drift)
+	}
+	want := "installed KubernetesCluster CRD lacks spec.topology.controlplane.architecture"
+	if !strings.HasPrefix(report.Drift[0].Message, want) {
+		t.Errorf("Drift message = %q, want prefix %q", report.Drift[0].Message, want)
+	}
+
+	// Limiting the check to fields the operator relies on ignores unrelated drift.
+	report, err = CheckCompatibility(context.Background(), newFakeDynamic(installed), expected, CompatibilityOptions{
+		RequiredFields: map[string][]string{"KubernetesCluster": {"spec.topology.workers.nodePools[].name"}},
+	})
+	if err != nil || !report.Compatible() {
+		t.Fatalf("expected compatible report, got %v, %v", report.Drift, err)
+	}
+}
+
+func TestCheckCompatibilityVersionsAndMissingCRD(t *testing.T) {
+	expected := loadExpected(t)
+
+	report, err := CheckCompatibility(context.Background(), newFakeDynamic(), expected, CompatibilityOptions{})
+	if err != nil || len(report.Drift) != 1 || !strings.Contains(report.Drift[0].Message, "is not installed") {
+		t.Fatalf("expected not-installed drift, got %v, %v", report.Drift, err)
+	}
+
+	installed := expected[0].DeepCopy()
+	versions, _, _ := unstructured.NestedSlice(installed.Object, "spec", "versions")
+	versions[0].(map[string]any)["served"] = false
+	versions[0].(map[string]any)["storage"] = false
+	_ = unstructured.SetNestedSlice(installed.Object, versions, "spec", "versions")
+
+	report, err = CheckCompatibility(context.Background(), newFakeDynamic(installed), expected, CompatibilityOptions{})
+	if err != nil || len(report.Drift) != 2 || report.Err() == nil {
+		t.Fatalf("expected storage and served drift, got %v, %v", report.Drift, err)
+	}
+}
+
+func TestEnsureCompatibleLogsDriftOnlyOnChange(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := vlog.IntoContext(context.Background(), vlog.New(vlog.Options{Writer: &buf}))
+	expected := loadExpected(t)
+
+	for range 3 {
+		if err := EnsureCompatible(ctx, newFakeDynamic(), expected, CompatibilityOptions{}); err == nil {
+			t.Fatalf("EnsureCompatible() expected error for a missing CRD")
+		}
+	}
+	if n := strings.Count(buf.String(), "CRD drift"); n != 1 {
+		t.Errorf("drift logged %d times, want once:\n%s", n, buf.String())
+	}
+
+	if err := EnsureCompatible(ctx, newFakeDynamic(expected[0]), expected, CompatibilityOptions{}); err != nil {
+		t.Fatalf("EnsureCompatible() unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "compatible again") {
+		t.Errorf("recovery not logged:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,116 @@
+package crdcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vitistack/common/pkg/clients/k8sclient"
+	"github.com/vitistack/common/pkg/loggers/vlog"
+	"github.com/vitistack/common/pkg/operator/reconcileutil"
+	"k8s.io/client-go/discovery"
+)
+
+// Waiter polls discovery until a set of CRDs is served. Its Ready method can be registered as a
+// readyz check so the operator reports not-ready, instead of crash-looping, while it waits.
+type Waiter struct {
+	Discovery discovery.DiscoveryInterface
+	Refs      []Ref
+	// BaseDelay and MaxDelay configure the backoff between polls (defaults 1s and 30s).
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	mu      sync.Mutex
+	missing []string
+	checked bool
+}
+
+// NewWaiter returns a Waiter for refs using the given discovery client.
+func NewWaiter(dc discovery.DiscoveryInterface, refs ...Ref) *Waiter {
+	return &Waiter{Discovery: dc, Refs: refs, BaseDelay: time.Second, MaxDelay: 30 * time.Second}
+}
+
+// WaitForInstalled blocks until all refs are served, using the global DiscoveryClient. A timeout of
+// zero waits until ctx is done. Use it instead of MustEnsureInstalled when CRDs and operators are
+// installed together.
+func WaitForInstalled(ctx context.Context, timeout time.Duration, refs ...Ref) error {
+	if k8sclient.DiscoveryClient == nil {
+		return errors.New("k8s discovery client not initialized; call k8sclient.Init() first")
+	}
+	return NewWaiter(k8sclient.DiscoveryClient, refs...).Wait(ctx, timeout)
+}
+
+// Wait polls discovery with backoff until every ref is served, ctx is done or timeout expires.
+// Discovery errors are logged and retried.
+func (w *Waiter) Wait(ctx context.Context, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	base, maxDelay := w.BaseDelay, w.MaxDelay
+	if base <= 0 {
+		base = time.Second
+	}
+	if maxDelay <= 0 {
+		maxDelay = 30 * time.Second
+	}
+
+	log := vlog.FromContext(ctx)
+	var last []string
+	for attempt := 0; ; attempt++ {
+		missing, err := findMissing(w.Discovery, w.Refs)
+		switch {
+		case err != nil:
+			log.Warn("Checking required CRDs failed", "attempt", attempt+1, "error", err)
+		case len(missing) == 0:
+			w.set(nil)
+			if attempt > 0 {
+				log.Info("All required CRDs/resources are installed", "attempts", attempt+1)
+			}
+			return nil
+		default:
+			w.set(missing)
+			if !slices.Equal(missing, last) {
+				log.Info("Waiting for CRDs/resources", "missing", strings.Join(missing, ", "))
+			} else {
+				log.Debug("Still waiting for CRDs/resources", "attempt", attempt+1, "missing", strings.Join(missing, ", "))
+			}
+			last = missing
+		}
+
+		select {
+		case <-ctx.Done():
+			if len(last) > 0 {
+				return fmt.Errorf("timed out waiting for CRDs/resources: %s: %w", strings.Join(last, ", "), ctx.Err())
+			}
+			return fmt.Errorf("timed out waiting for CRDs/resources: %w", ctx.Err())
+		case <-time.After(reconcileutil.Backoff(attempt, base, maxDelay)):
+		}
+	}
+}
+
+// Ready is a healthz.Checker that fails until Wait has seen every ref served.
+func (w *Waiter) Ready(_ *http.Request) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.checked {
+		return errors.New("required CRDs not checked yet")
+	}
+	if len(w.missing) > 0 {
+		return fmt.Errorf("waiting for CRDs/resources: %s", strings.Join(w.missing, ", "))
+	}
+	return nil
+}
+
+func (w *Waiter) set(missing []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.checked = true
+	w.missing = missing
+}
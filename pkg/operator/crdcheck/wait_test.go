@@ -0,0 +1,44 @@
+package crdcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestWaiterWaitsForCRDs(t *testing.T) {
+	dc := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}}
+	machines := Ref{Group: "vitistack.io", Version: "v1alpha1", Resource: "machines"}
+	w := NewWaiter(dc, machines)
+	w.BaseDelay, w.MaxDelay = time.Millisecond, 5*time.Millisecond
+
+	if err := w.Ready(nil); err == nil {
+		t.Fatalf("Ready() must fail before the first check")
+	}
+	ctx := context.Background()
+	if err := w.Wait(ctx, 20*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() error = %v, want deadline exceeded", err)
+	}
+	if err := w.Ready(&http.Request{}); err == nil {
+		t.Fatalf("Ready() must fail while CRDs are missing")
+	}
+
+	dc.Lock()
+	dc.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "vitistack.io/v1alpha1",
+		APIResources: []metav1.APIResource{{Name: "machines", Kind: "Machine"}},
+	}}
+	dc.Unlock()
+	if err := w.Wait(ctx, time.Second); err != nil {
+		t.Fatalf("Wait() unexpected error: %v", err)
+	}
+	if err := w.Ready(nil); err != nil {
+		t.Fatalf("Ready() unexpected error: %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package env
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -42,3 +43,18 @@ func GetDuration(key string, def time.Duration) time.Duration {
 	}
 	return def
 }
+
+// GetStringSlice splits a comma-separated value, trimming spaces and dropping empty items.
+func GetStringSlice(key string, def []string) []string {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	var out []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -350,3 +350,23 @@ func TestAllFunctionsWithRealEnvVars(t *testing.T) {
 		t.Errorf("GetDuration() = %v, want %v", d, 30*time.Second)
 	}
 }
+
+func TestGetStringSlice(t *testing.T) {
+	const key = "TEST_STRING_SLICE"
+	def := []string{defaultValue}
+
+	if got := GetStringSlice(key, def); len(got) != 1 || got[0] != defaultValue {
+		t.Errorf("GetStringSlice() unset = %v, want %v", got, def)
+	}
+
+	t.Setenv(key, " ns-a, ,ns-b ,")
+	got := GetStringSlice(key, def)
+	if len(got) != 2 || got[0] != "ns-a" || got[1] != "ns-b" {
+		t.Errorf("GetStringSlice() = %v, want [ns-a ns-b]", got)
+	}
+
+	t.Setenv(key, "")
+	if got := GetStringSlice(key, def); len(got) != 0 {
+		t.Errorf("GetStringSlice() empty = %v, want []", got)
+	}
+}
@@ -0,0 +1,349 @@
+package env
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vitistack/common/pkg/redact"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// FieldError describes a single missing or invalid variable found by Load.
+type FieldError struct {
+	Field string
+	Key   string
+	Value string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("%s (%s): %v", e.Key, e.Field, e.Err)
+	}
+	return fmt.Sprintf("%s=%q (%s): %v", e.Key, e.Value, e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+// ErrRequired is wrapped by FieldError for required variables that are not set.
+var ErrRequired = errors.New("required but not set")
+
+// LoadError aggregates every FieldError found by Load.
+type LoadError struct {
+	Errors []*FieldError
+}
+
+func (e *LoadError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		msgs = append(msgs, fe.Error())
+	}
+	return fmt.Sprintf("invalid environment configuration (%d errors):\n  - %s", len(e.Errors), strings.Join(msgs, "\n  - "))
+}
+
+// Unwrap exposes the individual field errors to errors.Is and errors.As.
+func (e *LoadError) Unwrap() []error {
+	out := make([]error, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		out = append(out, fe)
+	}
+	return out
+}
+
+// Lookup resolves a variable; it has the signature of os.LookupEnv.
+type Lookup func(key string) (string, bool)
+
+// Load fills the struct pointed to by cfg from environment variables, driven by struct tags:
+//
+//	type Config struct {
+//		S3Endpoint *url.URL          `env:"S3_ENDPOINT" required:"true"`
+//		Timeout    time.Duration     `env:"TIMEOUT" default:"30s"`
+//		Namespaces []string          `env:"WATCH_NAMESPACES"`          // comma separated
+//		Labels     map[string]string `env:"EXTRA_LABELS"`              // k1=v1,k2=v2
+//		Memory     resource.Quantity `env:"MEMORY_LIMIT" default:"512Mi"`
+//		S3         S3Config          `envPrefix:"S3_"`                 // nested struct
+//	}
+//
+// Supported field types are strings, bools, ints, uints, floats, time.Duration, *url.URL/url.URL,
+// resource.Quantity, types implementing encoding.TextUnmarshaler, and slices and maps of those.
+// Unlike the Get* helpers, invalid values are never replaced by the default: Load keeps going
+// and returns a *LoadError listing every missing or invalid variable.
+func Load(cfg any) error {
+	return LoadWith(cfg, os.LookupEnv)
+}
+
+// LoadWith is Load with a custom variable lookup, e.g. for layered configuration or tests.
+func LoadWith(cfg any, lookup Lookup) error {
+	rv := reflect.ValueOf(cfg)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("env.Load: expected a non-nil pointer to a struct, got %T", cfg)
+	}
+	var errs []*FieldError
+	loadStruct(rv.Elem(), "", "", lookup, &errs)
+	if len(errs) > 0 {
+		return &LoadError{Errors: errs}
+	}
+	return nil
+}
+
+// Field describes a tagged struct field as seen by Load.
+type Field struct {
+	// Path is the Go field path, e.g. "S3.Endpoint".
+	Path     string
+	Key      string
+	Default  string
+	Required bool
+	// Secret is set by the `secret:"true"` tag or when the variable or field name looks like a
+	// secret (see redact.IsSensitiveKey); such values should never be printed. Tag a field
+	// `redact:"false"` to opt out of the name check.
+	Secret bool
+	// Type is the Go type of the field.
+	Type reflect.Type
+}
+
+// Fields lists the tagged fields of cfg (a struct or pointer to struct) in declaration order.
+func Fields(cfg any) []Field {
+	t := reflect.TypeOf(cfg)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	var out []Field
+	collectFields(t, "", "", &out)
+	return out
+}
+
+func collectFields(t reflect.Type, path, prefix string, out *[]Field) {
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fieldPath := joinField(path, sf.Name)
+		key, ok := sf.Tag.Lookup("env")
+		if !ok {
+			if p, nested := sf.Tag.Lookup("envPrefix"); nested && sf.Type.Kind() == reflect.Struct {
+				collectFields(sf.Type, fieldPath, prefix+p, out)
+			}
+			continue
+		}
+		if key == "-" {
+			continue
+		}
+		*out = append(*out, Field{
+			Path:     fieldPath,
+			Key:      prefix + key,
+			Default:  sf.Tag.Get("default"),
+			Required: sf.Tag.Get("required") == "true",
+			Secret:   sensitive(sf, prefix+key),
+			Type:     sf.Type,
+		})
+	}
+}
+
+func loadStruct(v reflect.Value, path, prefix string, lookup Lookup, errs *[]*FieldError) {
+	t := v.Type()
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fieldPath := joinField(path, sf.Name)
+		key, ok := sf.Tag.Lookup("env")
+		if !ok {
+			if p, nested := sf.Tag.Lookup("envPrefix"); nested && sf.Type.Kind() == reflect.Struct {
+				loadStruct(v.Field(i), fieldPath, prefix+p, lookup, errs)
+			}
+			continue
+		}
+		if key == "-" {
+			continue
+		}
+		key = prefix + key
+
+		raw, set := lookup(key)
+		if !set {
+			if def, hasDef := sf.Tag.Lookup("default"); hasDef {
+				raw, set = def, true
+			}
+		}
+		if !set {
+			if sf.Tag.Get("required") == "true" {
+				*errs = append(*errs, &FieldError{Field: fieldPath, Key: key, Err: ErrRequired})
+			}
+			continue
+		}
+		if err := setValue(v.Field(i), raw, sf.Tag.Get("separator")); err != nil {
+			shown := raw
+			if sensitive(sf, key) && raw != "" {
+				shown = redact.Redacted
+				err = &redactedError{err: err, raw: raw}
+			}
+			*errs = append(*errs, &FieldError{Field: fieldPath, Key: key, Value: shown, Err: err})
+		}
+	}
+}
+
+// redactedError hides a secret value that err quotes, e.g. strconv.ParseInt: parsing "...".
+type redactedError struct {
+	err error
+	raw string
+}
+
+func (e *redactedError) Error() string {
+	return strings.ReplaceAll(e.err.Error(), e.raw, redact.Redacted)
+}
+
+func (e *redactedError) Unwrap() error { return e.err }
+
+// sensitive reports whether the value of a field must not be printed.
+func sensitive(sf reflect.StructField, key string) bool {
+	switch {
+	case sf.Tag.Get("secret") == "true" || sf.Tag.Get("redact") == "true":
+		return true
+	case sf.Tag.Get("redact") == "false":
+		return false
+	}
+	return redact.IsSensitiveKey(key) || redact.IsSensitiveKey(sf.Name)
+}
+
+var (
+	durationType = reflect.TypeFor[time.Duration]()
+	urlType      = reflect.TypeFor[url.URL]()
+	quantityType = reflect.TypeFor[resource.Quantity]()
+	textType     = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+func setValue(v reflect.Value, raw, sep string) error {
+	if sep == "" {
+		sep = ","
+	}
+	// Pointers are allocated on demand, e.g. *url.URL.
+	if v.Kind() == reflect.Pointer {
+		elem := reflect.New(v.Type().Elem())
+		if err := setValue(elem.Elem(), raw, sep); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	switch v.Type() {
+	case durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	case urlType:
+		u, err := url.Parse(raw)
+		if err != nil {
+			return err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%q is not an absolute URL", raw)
+		}
+		v.Set(reflect.ValueOf(*u))
+		return nil
+	case quantityType:
+		q, err := resource.ParseQuantity(raw)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(q))
+		return nil
+	}
+	if reflect.PointerTo(v.Type()).Implements(textType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		if raw == "" {
+			v.SetBool(true) // treat empty as true when present, like GetBool
+			return nil
+		}
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		items := splitList(raw, sep)
+		s := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setValue(s.Index(i), item, sep); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		v.Set(s)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		for _, item := range splitList(raw, sep) {
+			k, val, ok := strings.Cut(item, "=")
+			if !ok {
+				return fmt.Errorf("map entry %q is not key=value", item)
+			}
+			kv := reflect.New(v.Type().Key()).Elem()
+			if err := setValue(kv, strings.TrimSpace(k), sep); err != nil {
+				return fmt.Errorf("map key %q: %w", k, err)
+			}
+			vv := reflect.New(v.Type().Elem()).Elem()
+			if err := setValue(vv, strings.TrimSpace(val), sep); err != nil {
+				return fmt.Errorf("map value for %q: %w", k, err)
+			}
+			m.SetMapIndex(kv, vv)
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
+
+func splitList(raw, sep string) []string {
+	var out []string
+	for _, s := range strings.Split(raw, sep) {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func joinField(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
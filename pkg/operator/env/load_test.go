@@ -0,0 +1,127 @@
+package env
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type s3Config struct {
+	Endpoint  *url.URL `env:"ENDPOINT" required:"true"`
+	SecretKey string   `env:"SECRET_KEY" secret:"true"`
+}
+
+type testConfig struct {
+	Name       string            `env:"NAME" default:"operator"`
+	Debug      bool              `env:"DEBUG"`
+	Workers    int               `env:"WORKERS" default:"4"`
+	Timeout    time.Duration     `env:"TIMEOUT" default:"30s"`
+	Namespaces []string          `env:"NAMESPACES"`
+	Labels     map[string]string `env:"LABELS"`
+	Memory     resource.Quantity `env:"MEMORY" default:"512Mi"`
+	S3         s3Config          `envPrefix:"S3_"`
+	ignored    string            //nolint:unused // unexported fields are skipped
+}
+
+func mapLookup(m map[string]string) Lookup {
+	return func(k string) (string, bool) {
+		v, ok := m[k]
+		return v, ok
+	}
+}
+
+func TestLoadWith(t *testing.T) {
+	var cfg testConfig
+	err := LoadWith(&cfg, mapLookup(map[string]string{
+		"DEBUG":       "true",
+		"NAMESPACES":  "a, b",
+		"LABELS":      "team=infra,env=prod",
+		"S3_ENDPOINT": "https://s3.example.com",
+	}))
+	if err != nil {
+		t.Fatalf("LoadWith() unexpected error: %v", err)
+	}
+	if cfg.Name != "operator" || !cfg.Debug || cfg.Workers != 4 || cfg.Timeout != 30*time.Second {
+		t.Errorf("unexpected scalars: %+v", cfg)
+	}
+	if len(cfg.Namespaces) != 2 || cfg.Namespaces[1] != "b" || cfg.Labels["env"] != "prod" {
+		t.Errorf("unexpected collections: %v %v", cfg.Namespaces, cfg.Labels)
+	}
+	if cfg.Memory.String() != "512Mi" || cfg.S3.Endpoint.Host != "s3.example.com" {
+		t.Errorf("unexpected memory/url: %s %v", cfg.Memory.String(), cfg.S3.Endpoint)
+	}
+}
+
+func TestLoadAggregatesErrors(t *testing.T) {
+	var cfg testConfig
+	err := LoadWith(&cfg, mapLookup(map[string]string{
+		"WORKERS":       "many",
+		"TIMEOUT":       "soon",
+		"S3_SECRET_KEY": "",
+	}))
+	var le *LoadError
+	if !errors.As(err, &le) {
+		t.Fatalf("expected *LoadError, got %v", err)
+	}
+	if len(le.Errors) != 3 {
+		t.Fatalf("expected 3 errors, got %v", err)
+	}
+	if !errors.Is(err, ErrRequired) || !strings.Contains(err.Error(), "S3_ENDPOINT") {
+		t.Errorf("expected missing S3_ENDPOINT, got %v", err)
+	}
+	if cfg.Workers != 0 {
+		t.Errorf("invalid value must not fall back to default, got %d", cfg.Workers)
+	}
+}
+
+func TestLoadRedactsSensitiveValues(t *testing.T) {
+	var cfg struct {
+		Tagged   int `env:"TAGGED" secret:"true"`
+		Password int `env:"DB_PASSWORD"`
+		APIToken int `env:"AUTH"`
+		Port     int `env:"PORT"`
+	}
+	err := LoadWith(&cfg, mapLookup(map[string]string{
+		"TAGGED":      "hunter1",
+		"DB_PASSWORD": "hunter2",
+		"AUTH":        "hunter3",
+		"PORT":        "eighty",
+	}))
+	if err == nil || strings.Contains(err.Error(), "hunter") {
+		t.Fatalf("LoadWith() error must not contain secret values: %v", err)
+	}
+	if !strings.Contains(err.Error(), `PORT="eighty"`) {
+		t.Errorf("LoadWith() error should show non-secret values: %v", err)
+	}
+
+	secret := map[string]bool{}
+	for _, f := range Fields(&cfg) {
+		secret[f.Key] = f.Secret
+	}
+	if !secret["TAGGED"] || !secret["DB_PASSWORD"] || !secret["AUTH"] || secret["PORT"] {
+		t.Errorf("Fields() secret flags = %v", secret)
+	}
+}
+
+func TestLoadRejectsNonPointer(t *testing.T) {
+	if err := Load(testConfig{}); err == nil {
+		t.Fatalf("expected error for non-pointer")
+	}
+}
+
+func TestFields(t *testing.T) {
+	fields := Fields(&testConfig{})
+	var secret *Field
+	for i := range fields {
+		if fields[i].Key == "S3_SECRET_KEY" {
+			secret = &fields[i]
+		}
+	}
+	if secret == nil || !secret.Secret || secret.Path != "S3.SecretKey" {
+		t.Fatalf("Fields() = %+v", fields)
+	}
+}
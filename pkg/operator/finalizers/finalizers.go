@@ -4,6 +4,7 @@ import (
 	"context"
 	"slices"
 
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -14,32 +15,48 @@ func Has(obj client.Object, name string) bool {
 }
 
 // Ensure adds the finalizer to the object if missing and patches it.
-// Safe to call repeatedly. It performs a server-side patch to avoid conflicts.
+// Safe to call repeatedly. The patch carries the object's resourceVersion so it never
+// overwrites finalizers added by other controllers in the meantime; on conflict the object
+// is re-read and the change retried.
 func Ensure(ctx context.Context, c client.Client, obj client.Object, name string) error {
-	if Has(obj, name) {
-		return nil
-	}
-	// Make a shallow copy for patch base
-	base := obj.DeepCopyObject().(client.Object)
-	obj.SetFinalizers(append(obj.GetFinalizers(), name))
-	return c.Patch(ctx, obj, client.MergeFrom(base))
+	return patchFinalizers(ctx, c, obj, func(fins []string) ([]string, bool) {
+		if slices.Contains(fins, name) {
+			return fins, false
+		}
+		return append(fins, name), true
+	})
 }
 
 // Remove removes the finalizer from the object if present and patches it.
-// Safe to call repeatedly.
+// Safe to call repeatedly. Uses the same optimistic locking and conflict retry as Ensure.
 func Remove(ctx context.Context, c client.Client, obj client.Object, name string) error {
-	fins := obj.GetFinalizers()
-	idx := -1
-	for i := range fins {
-		if fins[i] == name {
-			idx = i
-			break
+	return patchFinalizers(ctx, c, obj, func(fins []string) ([]string, bool) {
+		idx := slices.Index(fins, name)
+		if idx < 0 {
+			return fins, false
+		}
+		return slices.Delete(slices.Clone(fins), idx, idx+1), true
+	})
+}
+
+// patchFinalizers applies mutate to the object's finalizers and patches it with an optimistic
+// lock. On a conflict the object is re-read and mutate is applied again.
+func patchFinalizers(ctx context.Context, c client.Client, obj client.Object, mutate func([]string) ([]string, bool)) error {
+	first := true
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !first {
+			if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+				return err
+			}
+		}
+		first = false
+		fins, changed := mutate(obj.GetFinalizers())
+		if !changed {
+			return nil
 		}
-	}
-	if idx < 0 {
-		return nil
-	}
-	base := obj.DeepCopyObject().(client.Object)
-	obj.SetFinalizers(append(fins[:idx], fins[idx+1:]...))
-	return c.Patch(ctx, obj, client.MergeFrom(base))
+		// Make a copy for patch base
+		base := obj.DeepCopyObject().(client.Object)
+		obj.SetFinalizers(fins)
+		return c.Patch(ctx, obj, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+	})
 }
@@ -7,6 +7,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -373,3 +374,34 @@ func TestRemoveIdempotent(t *testing.T) {
 		t.Errorf("Remove() should remove all instances, got finalizers: %v", obj.GetFinalizers())
 	}
 }
+
+func TestEnsureDoesNotClobberConcurrentFinalizers(t *testing.T) {
+	stored := &mockObject{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: mockObjectKind},
+		ObjectMeta: metav1.ObjectMeta{Name: testObjectName, Namespace: defaultNamespace},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(newTestScheme()).WithObjects(stored).Build()
+	ctx := context.Background()
+
+	stale := &mockObject{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(stored), stale); err != nil {
+		t.Fatal(err)
+	}
+	// Another controller adds its finalizer after we read the object.
+	other := stale.DeepCopyObject().(*mockObject)
+	other.SetFinalizers([]string{"other-controller"})
+	if err := fakeClient.Update(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Ensure(ctx, fakeClient, stale, testFinalizerName); err != nil {
+		t.Fatalf("Ensure() unexpected error: %v", err)
+	}
+	got := &mockObject{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(stored), got); err != nil {
+		t.Fatal(err)
+	}
+	if !Has(got, "other-controller") || !Has(got, testFinalizerName) {
+		t.Fatalf("expected both finalizers, got %v", got.GetFinalizers())
+	}
+}
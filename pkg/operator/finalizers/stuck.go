@@ -0,0 +1,276 @@
+package finalizers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vitistack/common/pkg/loggers/vlog"
+	"github.com/vitistack/common/pkg/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+)
+
+// ForceRemovedAnnotation records, as a JSON list, which finalizers were force-removed, when and
+// why. Every removal appends an entry. It is a secondary copy that goes away with the object;
+// the Event with reason ForceRemovedEventReason is the audit record.
+const ForceRemovedAnnotation = "vitistack.io/force-removed-finalizers"
+
+// ForceRemovedEventReason is the reason of the Warning Event that ForceRemove records on the
+// object for every removal.
+const ForceRemovedEventReason = "FinalizersForceRemoved"
+
+var eventsResource = schema.GroupVersionResource{Version: "v1", Resource: "events"}
+
+// StuckObject is an object that has been terminating for longer than the requested threshold.
+type StuckObject struct {
+	Resource          schema.GroupVersionResource
+	Kind              string
+	Namespace         string
+	Name              string
+	UID               types.UID
+	DeletionTimestamp time.Time
+	// Finalizers still blocking deletion.
+	Finalizers []string
+}
+
+// StuckFor returns how long the object has been terminating.
+func (s *StuckObject) StuckFor() time.Duration { return time.Since(s.DeletionTimestamp) }
+
+func (s *StuckObject) String() string {
+	name := s.Name
+	if s.Namespace != "" {
+		name = s.Namespace + "/" + name
+	}
+	return fmt.Sprintf("%s %s (terminating for %s, blocked by %s)",
+		s.Kind, name, s.StuckFor().Round(time.Second), strings.Join(s.Finalizers, ", "))
+}
+
+// ForceRemoval describes an audited request to strip finalizers from a stuck object.
+type ForceRemoval struct {
+	// Finalizers to strip. Finalizers not present on the object are ignored.
+	Finalizers []string
+	// Reason is required and recorded in the audit Event, the log and ForceRemovedAnnotation.
+	Reason string
+	// Actor identifies who requested the removal, e.g. a user name or tool.
+	Actor string
+}
+
+type forceRemovedRecord struct {
+	Finalizers []string  `json:"finalizers"`
+	Reason     string    `json:"reason"`
+	Actor      string    `json:"actor,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+// FindStuck lists all vitistack.io objects that have been terminating for longer than olderThan,
+// together with the finalizers that block them. Results are sorted by how long they have been stuck.
+func FindStuck(ctx context.Context, dc discovery.DiscoveryInterface, dyn dynamic.Interface, olderThan time.Duration) ([]StuckObject, error) {
+	gv := v1alpha1.GroupVersion
+	list, err := dc.ServerResourcesForGroupVersion(gv.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover resources for %s: %w", gv, err)
+	}
+
+	var stuck []StuckObject
+	for i := range list.APIResources {
+		res := &list.APIResources[i]
+		// Skip subresources such as machines/status.
+		if strings.Contains(res.Name, "/") || !slices.Contains(res.Verbs, "list") {
+			continue
+		}
+		gvr := gv.WithResource(res.Name)
+		items, err := dyn.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", gvr.Resource, err)
+		}
+		for j := range items.Items {
+			u := &items.Items[j]
+			ts := u.GetDeletionTimestamp()
+			if ts == nil || len(u.GetFinalizers()) == 0 || time.Since(ts.Time) < olderThan {
+				continue
+			}
+			stuck = append(stuck, StuckObject{
+				Resource:          gvr,
+				Kind:              res.Kind,
+				Namespace:         u.GetNamespace(),
+				Name:              u.GetName(),
+				UID:               u.GetUID(),
+				DeletionTimestamp: ts.Time,
+				Finalizers:        u.GetFinalizers(),
+			})
+		}
+	}
+	sort.SliceStable(stuck, func(a, b int) bool {
+		return stuck[a].DeletionTimestamp.Before(stuck[b].DeletionTimestamp)
+	})
+	return stuck, nil
+}
+
+// ForceRemove strips the requested finalizers from a stuck object. It only acts on the exact
+// object that was found (matching UID) and only while it is still terminating. Each removal is
+// recorded as a Warning Event on the object, which outlives it, logged, and appended to
+// ForceRemovedAnnotation. The caller needs permission to create events.
+func ForceRemove(ctx context.Context, dyn dynamic.Interface, obj *StuckObject, req ForceRemoval) error {
+	if strings.TrimSpace(req.Reason) == "" {
+		return errors.New("force removal requires a reason")
+	}
+	if len(req.Finalizers) == 0 {
+		return errors.New("force removal requires at least one finalizer")
+	}
+
+	ri := dyn.Resource(obj.Resource).Namespace(obj.Namespace)
+	var (
+		removedFrom *unstructured.Unstructured
+		record      forceRemovedRecord
+	)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		u, err := ri.Get(ctx, obj.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if obj.UID != "" && u.GetUID() != obj.UID {
+			return fmt.Errorf("%s was recreated (uid %s, expected %s); refusing to remove finalizers", obj.Name, u.GetUID(), obj.UID)
+		}
+		if u.GetDeletionTimestamp() == nil {
+			return fmt.Errorf("%s is not being deleted; refusing to remove finalizers", obj.Name)
+		}
+
+		var removed, remaining []string
+		for _, f := range u.GetFinalizers() {
+			if slices.Contains(req.Finalizers, f) {
+				removed = append(removed, f)
+			} else {
+				remaining = append(remaining, f)
+			}
+		}
+		if len(removed) == 0 {
+			return nil
+		}
+
+		records, err := forceRemovedRecords(u.GetAnnotations()[ForceRemovedAnnotation])
+		if err != nil {
+			return fmt.Errorf("%s: refusing to overwrite %s: %w", obj.Name, ForceRemovedAnnotation, err)
+		}
+		record = forceRemovedRecord{
+			Finalizers: removed,
+			Reason:     req.Reason,
+			Actor:      req.Actor,
+			Time:       time.Now().UTC(),
+		}
+		audit, err := json.Marshal(append(records, record))
+		if err != nil {
+			return err
+		}
+		patch, err := json.Marshal(map[string]any{
+			"metadata": map[string]any{
+				"resourceVersion": u.GetResourceVersion(),
+				"finalizers":      nonNil(remaining),
+				"annotations":     map[string]string{ForceRemovedAnnotation: string(audit)},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := ri.Patch(ctx, obj.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return err
+		}
+		removedFrom = u
+		return nil
+	})
+	if err != nil || removedFrom == nil {
+		return err
+	}
+
+	vlog.FromContext(ctx).Warn("force-removed finalizers",
+		"kind", obj.Kind,
+		"namespace", obj.Namespace,
+		"name", obj.Name,
+		"finalizers", strings.Join(record.Finalizers, ","),
+		"reason", req.Reason,
+		"actor", req.Actor)
+	if err := recordForceRemoval(ctx, dyn, removedFrom, record); err != nil {
+		return fmt.Errorf("%s: finalizers removed but the audit event was not recorded: %w", obj.Name, err)
+	}
+	return nil
+}
+
+// recordForceRemoval creates the audit Event for a removal. It writes the Event directly rather
+// than through an EventRecorder so that short-lived tools do not exit before it is sent.
+func recordForceRemoval(ctx context.Context, dyn dynamic.Interface, u *unstructured.Unstructured, rec forceRemovedRecord) error {
+	namespace := u.GetNamespace()
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	actor := rec.Actor
+	if actor == "" {
+		actor = "unknown actor"
+	}
+	ts := metav1.NewTime(rec.Time)
+	ev := &corev1.Event{
+		// Named like client-go's event recorder names its events.
+		ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%v.%x", u.GetName(), rec.Time.UnixNano()), Namespace: namespace},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion:      u.GetAPIVersion(),
+			Kind:            u.GetKind(),
+			Namespace:       u.GetNamespace(),
+			Name:            u.GetName(),
+			UID:             u.GetUID(),
+			ResourceVersion: u.GetResourceVersion(),
+		},
+		Type:           corev1.EventTypeWarning,
+		Reason:         ForceRemovedEventReason,
+		Message:        fmt.Sprintf("%s force-removed finalizers %s: %s", actor, strings.Join(rec.Finalizers, ", "), rec.Reason),
+		Source:         corev1.EventSource{Component: "vitistack-finalizers"},
+		FirstTimestamp: ts,
+		LastTimestamp:  ts,
+		Count:          1,
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ev)
+	if err != nil {
+		return err
+	}
+	_, err = dyn.Resource(eventsResource).Namespace(namespace).Create(ctx, &unstructured.Unstructured{Object: obj}, metav1.CreateOptions{})
+	return err
+}
+
+// forceRemovedRecords parses the existing audit annotation. A single record, as written by
+// earlier versions, is accepted too.
+func forceRemovedRecords(raw string) ([]forceRemovedRecord, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var records []forceRemovedRecord
+	if err := json.Unmarshal([]byte(raw), &records); err == nil {
+		return records, nil
+	}
+	var record forceRemovedRecord
+	if err := json.Unmarshal([]byte(raw), &record); err != nil {
+		return nil, err
+	}
+	return []forceRemovedRecord{record}, nil
+}
+
+// nonNil makes sure an empty finalizer list is sent as [] rather than null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
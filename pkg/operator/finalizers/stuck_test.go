@@ -0,0 +1,137 @@
+package finalizers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newMachine(name string, deletedAgo time.Duration, finalizers ...string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("vitistack.io/v1alpha1")
+	u.SetKind("Machine")
+	u.SetNamespace(defaultNamespace)
+	u.SetName(name)
+	u.SetUID(types.UID("uid-" + name))
+	u.SetFinalizers(finalizers)
+	if deletedAgo > 0 {
+		ts := metav1.NewTime(time.Now().Add(-deletedAgo))
+		u.SetDeletionTimestamp(&ts)
+	}
+	return u
+}
+
+func TestFindStuckAndForceRemove(t *testing.T) {
+	machines := schema.GroupVersionResource{Group: "vitistack.io", Version: "v1alpha1", Resource: "machines"}
+	dyn := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{machines: "MachineList", eventsResource: "EventList"},
+		newMachine("stuck", time.Hour, "vitistack.io/machine-cleanup", "other"),
+		newMachine("recent", time.Second, "vitistack.io/machine-cleanup"),
+		newMachine("alive", 0, "vitistack.io/machine-cleanup"),
+	)
+	dc := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}}
+	dc.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "vitistack.io/v1alpha1",
+		APIResources: []metav1.APIResource{
+			{Name: "machines", Kind: "Machine", Namespaced: true, Verbs: metav1.Verbs{"get", "list", "patch"}},
+			{Name: "machines/status", Kind: "Machine", Namespaced: true, Verbs: metav1.Verbs{"get", "patch"}},
+		},
+	}}
+	ctx := context.Background()
+
+	stuck, err := FindStuck(ctx, dc, dyn, 10*time.Minute)
+	if err != nil {
+		t.Fatalf("FindStuck() unexpected error: %v", err)
+	}
+	if len(stuck) != 1 || stuck[0].Name != "stuck" || len(stuck[0].Finalizers) != 2 {
+		t.Fatalf("FindStuck() = %v, want only the stuck machine", stuck)
+	}
+	if !strings.Contains(stuck[0].String(), "blocked by vitistack.io/machine-cleanup, other") {
+		t.Errorf("StuckObject.String() = %q", stuck[0].String())
+	}
+
+	if err := ForceRemove(ctx, dyn, &stuck[0], ForceRemoval{Finalizers: []string{"vitistack.io/machine-cleanup"}}); err == nil {
+		t.Fatalf("ForceRemove() without reason should fail")
+	}
+	req := ForceRemoval{Finalizers: []string{"vitistack.io/machine-cleanup"}, Reason: "provider gone", Actor: "ops"}
+	if err := ForceRemove(ctx, dyn, &stuck[0], req); err != nil {
+		t.Fatalf("ForceRemove() unexpected error: %v", err)
+	}
+	got, err := dyn.Resource(machines).Namespace(defaultNamespace).Get(ctx, "stuck", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := got.GetFinalizers(); len(f) != 1 || f[0] != "other" {
+		t.Errorf("finalizers after ForceRemove = %v, want [other]", f)
+	}
+	if !strings.Contains(got.GetAnnotations()[ForceRemovedAnnotation], "provider gone") {
+		t.Errorf("expected audit annotation, got %v", got.GetAnnotations())
+	}
+
+	// A second removal appends to the audit trail instead of replacing it.
+	req = ForceRemoval{Finalizers: []string{"other"}, Reason: "orphaned", Actor: "ops"}
+	if err := ForceRemove(ctx, dyn, &stuck[0], req); err != nil {
+		t.Fatalf("ForceRemove() unexpected error: %v", err)
+	}
+	got, err = dyn.Resource(machines).Namespace(defaultNamespace).Get(ctx, "stuck", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []forceRemovedRecord
+	if err := json.Unmarshal([]byte(got.GetAnnotations()[ForceRemovedAnnotation]), &records); err != nil {
+		t.Fatalf("audit annotation is not a JSON list: %v", err)
+	}
+	if len(records) != 2 || records[0].Reason != "provider gone" || records[1].Reason != "orphaned" {
+		t.Errorf("audit records = %+v, want both removals in order", records)
+	}
+
+	// Each removal is also recorded as an Event, which outlives the object.
+	events, err := dyn.Resource(eventsResource).Namespace(defaultNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events.Items) != 2 {
+		t.Fatalf("recorded %d events, want 2", len(events.Items))
+	}
+	for _, ev := range events.Items {
+		reason, _, _ := unstructured.NestedString(ev.Object, "reason")
+		name, _, _ := unstructured.NestedString(ev.Object, "involvedObject", "name")
+		msg, _, _ := unstructured.NestedString(ev.Object, "message")
+		if reason != ForceRemovedEventReason || name != "stuck" || !strings.HasPrefix(msg, "ops force-removed finalizers") {
+			t.Errorf("unexpected event: reason=%q object=%q message=%q", reason, name, msg)
+		}
+	}
+}
+
+func TestForceRemovedRecords(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{name: "empty", raw: ""},
+		{name: "list", raw: `[{"finalizers":["a"],"reason":"r1"},{"finalizers":["b"],"reason":"r2"}]`, want: 2},
+		{name: "single record", raw: `{"finalizers":["a"],"reason":"r1"}`, want: 1},
+		{name: "garbage", raw: "not json", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := forceRemovedRecords(tt.raw)
+			if (err != nil) != tt.wantErr || len(got) != tt.want {
+				t.Errorf("forceRemovedRecords(%q) = %d records, %v", tt.raw, len(got), err)
+			}
+		})
+	}
+}
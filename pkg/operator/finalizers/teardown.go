@@ -0,0 +1,264 @@
+package finalizers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vitistack/common/pkg/loggers/vlog"
+	"github.com/vitistack/common/pkg/operator/reconcileutil"
+	"github.com/vitistack/common/pkg/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TeardownObject is an object whose status records teardown progress, such as
+// v1alpha1.Machine and v1alpha1.KubernetesCluster. Keeping the progress in status lets it
+// survive operator restarts and leader changes.
+type TeardownObject interface {
+	client.Object
+	GetTeardownSteps() []v1alpha1.TeardownStepStatus
+	SetTeardownSteps(steps []v1alpha1.TeardownStepStatus)
+}
+
+// StepFunc performs one cleanup step for obj. It must be idempotent: a step may run again
+// after an operator restart even if it already succeeded.
+type StepFunc func(ctx context.Context, obj client.Object) error
+
+// Step is an ordered cleanup step of a Teardown.
+type Step struct {
+	Name string
+	Run  StepFunc
+	// Timeout bounds a single attempt. Zero means no timeout beyond the caller's context.
+	Timeout time.Duration
+	// Attempts caps the number of failed attempts across reconciles. Once reached, Run returns a
+	// terminal error and the finalizer stays until someone intervenes (see ForceRemove).
+	// Zero means retry forever.
+	Attempts int
+	// RetryBase and RetryMax configure the jittered exponential backoff that Run requeues with
+	// after a failed attempt (defaults 1s and 30s).
+	RetryBase time.Duration
+	RetryMax  time.Duration
+}
+
+// StepState is the state of a cleanup step.
+type StepState = v1alpha1.TeardownStepState
+
+const (
+	StepPending   = v1alpha1.TeardownStepPending
+	StepRunning   = v1alpha1.TeardownStepRunning
+	StepSucceeded = v1alpha1.TeardownStepSucceeded
+	StepFailed    = v1alpha1.TeardownStepFailed
+)
+
+// StepStatus tracks the progress of a single step.
+type StepStatus = v1alpha1.TeardownStepStatus
+
+// Progress is the state of all steps of a Teardown for one object, in execution order.
+type Progress struct {
+	Steps []StepStatus
+}
+
+// Done returns true when every step has succeeded.
+func (p Progress) Done() bool {
+	for i := range p.Steps {
+		if p.Steps[i].State != StepSucceeded {
+			return false
+		}
+	}
+	return true
+}
+
+// Message renders a short human-readable summary suitable for a status message, e.g.
+// "teardown 2/4 done; DeleteVM failed (attempt 3): timeout".
+func (p Progress) Message() string {
+	done := 0
+	var current *StepStatus
+	for i := range p.Steps {
+		if p.Steps[i].State == StepSucceeded {
+			done++
+			continue
+		}
+		if current == nil {
+			current = &p.Steps[i]
+		}
+	}
+	msg := fmt.Sprintf("teardown %d/%d done", done, len(p.Steps))
+	if current == nil || current.State == StepPending {
+		return msg
+	}
+	msg += fmt.Sprintf("; %s %s", current.Name, strings.ToLower(string(current.State)))
+	if current.Attempts > 1 {
+		msg += fmt.Sprintf(" (attempt %d)", current.Attempts)
+	}
+	if current.Message != "" {
+		msg += ": " + current.Message
+	}
+	return msg
+}
+
+// Teardown runs ordered cleanup steps for an object being deleted and removes the finalizer
+// only once every step has succeeded. Progress is kept in the object's status
+// (Status.TeardownSteps), so a Teardown holds no per-object state and can be shared by all
+// workers.
+//
+// Example:
+//
+//	td := finalizers.NewTeardown("vitistack.io/machine-cleanup").
+//		Add(finalizers.Step{Name: "ReleaseIPs", Run: releaseIPs, Timeout: 30 * time.Second}).
+//		Add(finalizers.Step{Name: "DeleteNetworkConfiguration", Run: deleteNetworkConfiguration}).
+//		Add(finalizers.Step{Name: "DeleteVM", Run: deleteVM, Timeout: 5 * time.Minute, Attempts: 10}).
+//		Add(finalizers.Step{Name: "DeleteBackups", Run: deleteBackups})
+//	td.OnProgress = func(obj finalizers.TeardownObject, p finalizers.Progress) {
+//		obj.(*v1alpha1.Machine).SetPhase(v1alpha1.MachinePhaseTerminating, p.Message())
+//	}
+//
+//	if !machine.DeletionTimestamp.IsZero() {
+//		return td.Run(ctx, r.Client, machine)
+//	}
+type Teardown struct {
+	Finalizer string
+	Steps     []Step
+	// OnProgress is called before progress is written to the object's status, so callers can
+	// set related status fields, such as the phase message, in the same patch.
+	OnProgress func(obj TeardownObject, p Progress)
+}
+
+// NewTeardown creates a Teardown guarded by the given finalizer name.
+func NewTeardown(finalizer string) *Teardown {
+	return &Teardown{Finalizer: finalizer}
+}
+
+// Add appends a step and returns the Teardown for chaining.
+func (t *Teardown) Add(s Step) *Teardown {
+	t.Steps = append(t.Steps, s)
+	return t
+}
+
+// Run makes one attempt at each remaining step in order, skipping steps that already succeeded
+// for this object. It never sleeps: when a step fails, Run saves the progress and returns a
+// result that requeues after the step's backoff, with a nil error so controller-runtime honours
+// it. When every step has succeeded the finalizer is removed. Errors are returned for failed API
+// calls and, as terminal errors, for steps that used up their Attempts. Progress is written
+// through the status subresource.
+func (t *Teardown) Run(ctx context.Context, c client.Client, obj TeardownObject) (ctrl.Result, error) {
+	if !Has(obj, t.Finalizer) {
+		return ctrl.Result{}, nil
+	}
+	p := t.Progress(obj)
+
+	for i := range t.Steps {
+		step, st := &t.Steps[i], &p.Steps[i]
+		if st.State == StepSucceeded {
+			continue
+		}
+		if step.Attempts > 0 && st.Attempts >= step.Attempts {
+			return ctrl.Result{}, reconcileutil.Terminal(fmt.Errorf("teardown step %q failed %d times: %s", st.Name, st.Attempts, st.Message))
+		}
+
+		st.Attempts++
+		transition(st, StepRunning, "")
+		if err := t.save(ctx, c, obj, p); err != nil {
+			return ctrl.Result{}, err
+		}
+		err := t.attempt(ctx, obj, step)
+		if err == nil {
+			transition(st, StepSucceeded, "")
+			if err := t.save(ctx, c, obj, p); err != nil {
+				return ctrl.Result{}, err
+			}
+			continue
+		}
+
+		vlog.FromContext(ctx).Warn("teardown step failed",
+			"step", step.Name,
+			"namespace", obj.GetNamespace(),
+			"name", obj.GetName(),
+			"attempt", st.Attempts,
+			"error", err)
+		transition(st, StepFailed, err.Error())
+		if err := t.save(ctx, c, obj, p); err != nil {
+			return ctrl.Result{}, err
+		}
+		if step.Attempts > 0 && st.Attempts >= step.Attempts {
+			return ctrl.Result{}, reconcileutil.Terminal(fmt.Errorf("teardown step %q failed %d times: %w", st.Name, st.Attempts, err))
+		}
+		base, maxDelay := step.RetryBase, step.RetryMax
+		if base <= 0 {
+			base = time.Second
+		}
+		if maxDelay <= 0 {
+			maxDelay = 30 * time.Second
+		}
+		return ctrl.Result{RequeueAfter: reconcileutil.Backoff(st.Attempts-1, base, maxDelay)}, nil
+	}
+
+	if err := Remove(ctx, c, obj, t.Finalizer); err != nil {
+		return ctrl.Result{}, fmt.Errorf("remove finalizer %q: %w", t.Finalizer, err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// Progress returns the teardown progress saved in the status of obj. Steps without a saved
+// state are Pending.
+func (t *Teardown) Progress(obj TeardownObject) Progress {
+	saved := obj.GetTeardownSteps()
+	p := Progress{Steps: make([]StepStatus, len(t.Steps))}
+	for i := range t.Steps {
+		p.Steps[i] = StepStatus{Name: t.Steps[i].Name, State: StepPending}
+		for _, st := range saved {
+			if st.Name == t.Steps[i].Name {
+				p.Steps[i] = st
+				break
+			}
+		}
+	}
+	return p
+}
+
+func (t *Teardown) attempt(ctx context.Context, obj client.Object, step *Step) error {
+	if step.Run == nil {
+		return nil
+	}
+	if step.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, step.Timeout)
+		defer cancel()
+	}
+	return step.Run(ctx, obj)
+}
+
+func transition(st *StepStatus, state StepState, msg string) {
+	if st.State != state {
+		st.LastTransitionTime = metav1.Now()
+	}
+	st.State = state
+	st.Message = msg
+}
+
+// save writes p to the status of obj with an optimistic lock, re-reading the object and
+// retrying on conflict.
+func (t *Teardown) save(ctx context.Context, c client.Client, obj TeardownObject, p Progress) error {
+	first := true
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !first {
+			if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+				return err
+			}
+		}
+		first = false
+		base := obj.DeepCopyObject().(client.Object)
+		obj.SetTeardownSteps(append([]StepStatus(nil), p.Steps...))
+		if t.OnProgress != nil {
+			t.OnProgress(obj, Progress{Steps: obj.GetTeardownSteps()})
+		}
+		return c.Status().Patch(ctx, obj, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+	})
+	if err != nil {
+		return fmt.Errorf("save teardown progress: %w", err)
+	}
+	return nil
+}
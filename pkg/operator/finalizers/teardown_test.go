@@ -0,0 +1,121 @@
+package finalizers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vitistack/common/pkg/operator/reconcileutil"
+	"github.com/vitistack/common/pkg/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTeardownMachine(t *testing.T) (*v1alpha1.Machine, client.Client) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	m := &v1alpha1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testObjectName,
+			Namespace:  defaultNamespace,
+			Finalizers: []string{testFinalizerName},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(m).WithStatusSubresource(m).Build()
+	return m, c
+}
+
+func TestTeardownRunsStepsInOrder(t *testing.T) {
+	obj, fakeClient := newTeardownMachine(t)
+	ctx := context.Background()
+
+	var calls []string
+	failDeleteVM := true
+	step := func(name string) StepFunc {
+		return func(_ context.Context, _ client.Object) error {
+			calls = append(calls, name)
+			if name == "DeleteVM" && failDeleteVM {
+				return errors.New("vm still running")
+			}
+			return nil
+		}
+	}
+	newTeardown := func() *Teardown {
+		td := NewTeardown(testFinalizerName).
+			Add(Step{Name: "ReleaseIPs", Run: step("ReleaseIPs")}).
+			Add(Step{Name: "DeleteVM", Run: step("DeleteVM"), Attempts: 3, RetryBase: time.Second, RetryMax: time.Minute}).
+			Add(Step{Name: "DeleteBackups", Run: step("DeleteBackups")})
+		td.OnProgress = func(obj TeardownObject, p Progress) {
+			obj.(*v1alpha1.Machine).SetPhase(v1alpha1.MachinePhaseTerminating, p.Message())
+		}
+		return td
+	}
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		res, err := newTeardown().Run(ctx, fakeClient, obj)
+		if err != nil {
+			t.Fatalf("Run() unexpected error: %v", err)
+		}
+		if res.RequeueAfter <= 0 {
+			t.Fatalf("Run() should requeue after a backoff when a step fails, got %+v", res)
+		}
+		if !Has(obj, testFinalizerName) {
+			t.Fatalf("finalizer must not be removed while a step fails")
+		}
+	}
+
+	// A new Teardown, as after an operator restart, picks up the progress saved in status.
+	stored := &v1alpha1.Machine{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(obj), stored); err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+	p := newTeardown().Progress(stored)
+	if p.Steps[0].State != StepSucceeded || p.Steps[1].State != StepFailed || p.Steps[1].Attempts != 2 {
+		t.Fatalf("unexpected saved progress: %+v", p.Steps)
+	}
+	if got := stored.Status.Message; got != "teardown 1/3 done; DeleteVM failed (attempt 2): vm still running" {
+		t.Fatalf("status message = %q, want the OnProgress message", got)
+	}
+
+	failDeleteVM = false
+	res, err := newTeardown().Run(ctx, fakeClient, stored)
+	if err != nil || res.RequeueAfter != 0 {
+		t.Fatalf("Run() = %+v, %v", res, err)
+	}
+	if Has(stored, testFinalizerName) {
+		t.Fatalf("expected finalizer removed once all steps are done")
+	}
+
+	want := []string{"ReleaseIPs", "DeleteVM", "DeleteVM", "DeleteVM", "DeleteBackups"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("calls = %v, want %v", calls, want)
+		}
+	}
+}
+
+func TestTeardownGivesUpAfterAttempts(t *testing.T) {
+	obj, fakeClient := newTeardownMachine(t)
+	td := NewTeardown(testFinalizerName).
+		Add(Step{Name: "DeleteVM", Attempts: 2, Run: func(context.Context, client.Object) error { return errors.New("boom") }})
+
+	if _, err := td.Run(context.Background(), fakeClient, obj); err != nil {
+		t.Fatalf("first attempt should requeue, got %v", err)
+	}
+	_, err := td.Run(context.Background(), fakeClient, obj)
+	if !reconcileutil.IsTerminal(err) {
+		t.Fatalf("Run() error = %v, want terminal after 2 attempts", err)
+	}
+	if !Has(obj, testFinalizerName) {
+		t.Fatalf("finalizer must be kept when a step gives up")
+	}
+}
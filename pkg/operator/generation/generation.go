@@ -0,0 +1,171 @@
+package generation
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/vitistack/common/pkg/operator/conditions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ObservedGeneration returns status.observedGeneration of obj. It works for typed objects
+// (e.g. NetworkNamespace, ControlPlaneVirtualSharedIP, Vitistack) and unstructured ones.
+// The bool is false when the status does not carry the field.
+func ObservedGeneration(obj client.Object) (int64, bool) {
+	m, err := toMap(obj)
+	if err != nil {
+		return 0, false
+	}
+	g, found, err := unstructured.NestedInt64(m, "status", "observedGeneration")
+	if err != nil || !found {
+		return 0, false
+	}
+	return g, true
+}
+
+// IsUpToDate returns true when the status of obj has observed its current metadata.generation.
+func IsUpToDate(obj client.Object) bool {
+	g, ok := ObservedGeneration(obj)
+	return ok && g >= obj.GetGeneration()
+}
+
+// Conditions returns the metav1-style conditions found under status.conditions of obj.
+// Conditions of the project's own types are returned too, with ObservedGeneration left at 0.
+func Conditions(obj client.Object) []metav1.Condition {
+	conds, _ := readConditions(obj)
+	return conds
+}
+
+// StaleConditions returns the types of the conditions on obj that were computed for an older
+// generation than metadata.generation. Conditions that do not record an observedGeneration,
+// such as those of the project's own types, cannot be stale and are skipped.
+func StaleConditions(obj client.Object) []string {
+	conds, tracked := readConditions(obj)
+	var out []string
+	for i := range conds {
+		if tracked[i] && conditions.IsStale(&conds[i], obj.GetGeneration()) {
+			out = append(out, conds[i].Type)
+		}
+	}
+	return out
+}
+
+// readConditions returns the conditions of obj and, for each, whether it records an
+// observedGeneration.
+func readConditions(obj client.Object) ([]metav1.Condition, []bool) {
+	m, err := toMap(obj)
+	if err != nil {
+		return nil, nil
+	}
+	raw, found, err := unstructured.NestedSlice(m, "status", "conditions")
+	if err != nil || !found {
+		return nil, nil
+	}
+	out := make([]metav1.Condition, 0, len(raw))
+	tracked := make([]bool, 0, len(raw))
+	for _, r := range raw {
+		cm, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		c := metav1.Condition{}
+		c.Type, _, _ = unstructured.NestedString(cm, "type")
+		status, _, _ := unstructured.NestedString(cm, "status")
+		c.Status = metav1.ConditionStatus(status)
+		c.Reason, _, _ = unstructured.NestedString(cm, "reason")
+		c.Message, _, _ = unstructured.NestedString(cm, "message")
+		var hasGen bool
+		c.ObservedGeneration, hasGen, _ = unstructured.NestedInt64(cm, "observedGeneration")
+		out = append(out, c)
+		tracked = append(tracked, hasGen)
+	}
+	return out, tracked
+}
+
+// WaitOption configures WaitForGeneration.
+type WaitOption func(*waitOptions)
+
+type waitOptions struct {
+	interval   time.Duration
+	timeout    time.Duration
+	conditions []string
+}
+
+// WithInterval sets the poll interval (default 1s).
+func WithInterval(d time.Duration) WaitOption {
+	return func(o *waitOptions) { o.interval = d }
+}
+
+// WithTimeout bounds the total wait (default: until ctx is done).
+func WithTimeout(d time.Duration) WaitOption {
+	return func(o *waitOptions) { o.timeout = d }
+}
+
+// WithConditions additionally waits until the given condition types exist and are current.
+// Conditions that do not record an observedGeneration only have to exist.
+func WithConditions(types ...string) WaitOption {
+	return func(o *waitOptions) { o.conditions = append(o.conditions, types...) }
+}
+
+// WaitForGeneration polls obj until status.observedGeneration (and any conditions requested
+// with WithConditions) has caught up with generation. obj must have its name and namespace set;
+// it is refreshed in place on every poll, so on success it holds the up-to-date object.
+//
+// Example:
+//
+//	_ = c.Update(ctx, ns)
+//	err := generation.WaitForGeneration(ctx, c, ns, ns.Generation,
+//		generation.WithTimeout(2*time.Minute), generation.WithConditions("Ready"))
+func WaitForGeneration(ctx context.Context, c client.Reader, obj client.Object, generation int64, opts ...WaitOption) error {
+	o := waitOptions{interval: time.Second}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+	}
+
+	key := client.ObjectKeyFromObject(obj)
+	var lastErr error
+	err := wait.PollUntilContextCancel(ctx, o.interval, true, func(ctx context.Context) (bool, error) {
+		if err := c.Get(ctx, key, obj); err != nil {
+			lastErr = err
+			return false, nil
+		}
+		lastErr = nil
+		if g, ok := ObservedGeneration(obj); !ok || g < generation {
+			return false, nil
+		}
+		conds, tracked := readConditions(obj)
+		for _, t := range o.conditions {
+			i := slices.IndexFunc(conds, func(c metav1.Condition) bool { return c.Type == t })
+			if i < 0 || (tracked[i] && conditions.IsStale(&conds[i], generation)) {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		if lastErr != nil {
+			return fmt.Errorf("waiting for %s to observe generation %d: %w (last error: %w)", key, generation, err, lastErr)
+		}
+		g, _ := ObservedGeneration(obj)
+		return fmt.Errorf("waiting for %s to observe generation %d (observed %d): %w", key, generation, g, err)
+	}
+	return nil
+}
+
+func toMap(obj runtime.Object) (map[string]any, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u.Object, nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
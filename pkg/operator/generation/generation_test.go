@@ -0,0 +1,81 @@
+package generation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vitistack/common/pkg/operator/conditions"
+	"github.com/vitistack/common/pkg/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newNetworkNamespace(gen, observed int64, conds ...metav1.Condition) *v1alpha1.NetworkNamespace {
+	return &v1alpha1.NetworkNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "nn", Namespace: "default", Generation: gen},
+		Status: v1alpha1.NetworkNamespaceStatus{
+			ObservedGeneration: observed,
+			Conditions:         conds,
+		},
+	}
+}
+
+func TestIsUpToDate(t *testing.T) {
+	if !IsUpToDate(newNetworkNamespace(2, 2)) {
+		t.Errorf("expected status to be up to date")
+	}
+	if IsUpToDate(newNetworkNamespace(3, 2)) {
+		t.Errorf("expected status to be stale")
+	}
+	if IsUpToDate(newNetworkNamespace(1, 0)) {
+		t.Errorf("expected missing observedGeneration to be stale")
+	}
+}
+
+func TestStaleConditions(t *testing.T) {
+	obj := newNetworkNamespace(3, 3,
+		conditions.New("Ready", metav1.ConditionTrue, "Ok", "", 2),
+		conditions.New("VlanAllocated", metav1.ConditionTrue, "Ok", "", 3),
+	)
+	stale := StaleConditions(obj)
+	if len(stale) != 1 || stale[0] != "Ready" {
+		t.Fatalf("StaleConditions() = %v, want [Ready]", stale)
+	}
+}
+
+func TestStaleConditions_SkipsUntrackedConditions(t *testing.T) {
+	m := &v1alpha1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "m", Generation: 4},
+		Status: v1alpha1.MachineStatus{
+			Conditions: []v1alpha1.MachineCondition{{Type: "Ready", Status: "True"}},
+		},
+	}
+	if conds := Conditions(m); len(conds) != 1 || conds[0].Type != "Ready" {
+		t.Fatalf("Conditions() = %v, want [Ready]", conds)
+	}
+	if stale := StaleConditions(m); len(stale) != 0 {
+		t.Errorf("StaleConditions() = %v, want none for conditions without observedGeneration", stale)
+	}
+}
+
+func TestWaitForGeneration(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	stored := newNetworkNamespace(2, 2, conditions.New("Ready", metav1.ConditionTrue, "Ok", "", 2))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(stored).Build()
+	ctx := context.Background()
+
+	obj := &v1alpha1.NetworkNamespace{ObjectMeta: metav1.ObjectMeta{Name: "nn", Namespace: "default"}}
+	if err := WaitForGeneration(ctx, c, obj, 2, WithInterval(10*time.Millisecond), WithConditions("Ready")); err != nil {
+		t.Fatalf("WaitForGeneration() unexpected error: %v", err)
+	}
+
+	err := WaitForGeneration(ctx, c, obj, 3, WithInterval(10*time.Millisecond), WithTimeout(50*time.Millisecond))
+	if err == nil {
+		t.Fatalf("WaitForGeneration() expected timeout error")
+	}
+}
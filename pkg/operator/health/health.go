@@ -0,0 +1,187 @@
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vitistack/common/pkg/clients/s3client/s3interface"
+	"github.com/vitistack/common/pkg/loggers/vlog"
+	"github.com/vitistack/common/pkg/operator/crdcheck"
+	"github.com/vitistack/common/pkg/v1alpha1"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+const (
+	// DefaultTimeout bounds a single check run when Check.Timeout is zero.
+	DefaultTimeout = 5 * time.Second
+	// DefaultCacheTTL is how long a result is reused when Check.CacheTTL is zero.
+	DefaultCacheTTL = 10 * time.Second
+)
+
+// Func performs a check. It should honour ctx; checks that do not are abandoned when the
+// timeout expires.
+type Func func(ctx context.Context) error
+
+// Check is a named health or readiness check with its own timeout and result caching.
+type Check struct {
+	Name string
+	Run  Func
+	// Timeout bounds a single run (default DefaultTimeout).
+	Timeout time.Duration
+	// CacheTTL is how long the last result is reused so frequent probes do not hammer the
+	// dependency (default DefaultCacheTTL). A negative value disables caching.
+	CacheTTL time.Duration
+}
+
+// Checker adapts c to a controller-runtime healthz.Checker for mgr.AddReadyzCheck/AddHealthzCheck.
+// Each call to Checker creates an independent result cache.
+func (c Check) Checker() healthz.Checker {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ttl := c.CacheTTL
+	if ttl == 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	var (
+		mu      sync.Mutex
+		checked time.Time
+		last    error
+	)
+	return func(req *http.Request) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if ttl > 0 && !checked.IsZero() && time.Since(checked) < ttl {
+			return last
+		}
+
+		ctx := req.Context()
+		err := run(ctx, timeout, c.Run)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", c.Name, err)
+			if last == nil {
+				vlog.FromContext(ctx).Warn("health check failing", "check", c.Name, "error", err)
+			}
+		} else if last != nil {
+			vlog.FromContext(ctx).Info("health check recovered", "check", c.Name)
+		}
+		checked, last = time.Now(), err
+		return err
+	}
+}
+
+func run(ctx context.Context, timeout time.Duration, fn Func) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- fn(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out after %s: %w", timeout, ctx.Err())
+	}
+}
+
+// CRDs checks that the given CRD-backed resources are still served.
+func CRDs(dc discovery.DiscoveryInterface, refs ...crdcheck.Ref) Check {
+	return Check{
+		Name: "crds",
+		Run: func(ctx context.Context) error {
+			return crdcheck.EnsureInstalled(ctx, dc, refs)
+		},
+	}
+}
+
+// APIServer checks that the Kubernetes API server is reachable by requesting /version.
+func APIServer(dc discovery.DiscoveryInterface) Check {
+	return Check{
+		Name: "apiserver",
+		Run: func(ctx context.Context) error {
+			rc := dc.RESTClient()
+			if rc == nil {
+				// Fake discovery clients have no REST client.
+				_, err := dc.ServerVersion()
+				return err
+			}
+			return rc.Get().AbsPath("/version").Do(ctx).Error()
+		},
+	}
+}
+
+// CacheSynced checks that the informer cache has synced. It fails until the manager has started.
+func CacheSynced(c cache.Cache) Check {
+	return Check{
+		Name: "cache-synced",
+		Run: func(ctx context.Context) error {
+			if !c.WaitForCacheSync(ctx) {
+				return errors.New("informer cache not synced")
+			}
+			return nil
+		},
+		// Once synced the result does not change; probing often keeps startup snappy.
+		CacheTTL: time.Second,
+	}
+}
+
+// S3 checks that the S3 backend is reachable by listing a prefix that should be empty.
+func S3(c s3interface.S3Client) Check {
+	return Check{
+		Name: "s3",
+		Run: func(ctx context.Context) error {
+			_, err := c.ListObject(ctx, s3interface.ListObjectsOptions{Prefix: ".healthz/"})
+			return err
+		},
+	}
+}
+
+// MachineProvider checks that a MachineProvider endpoint answers HTTP requests. Any response
+// below 500 counts as reachable. The endpoint's TLS settings and timeout are honoured.
+func MachineProvider(name string, ep v1alpha1.ProviderEndpoint) (Check, error) {
+	if ep.URL == "" {
+		return Check{}, fmt.Errorf("machine provider %s has no endpoint URL", name)
+	}
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: ep.InsecureSkipVerify} // #nosec G402 -- opt-in per provider
+	if ep.CABundle != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(ep.CABundle)) {
+			return Check{}, fmt.Errorf("machine provider %s: invalid CA bundle", name)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsCfg}}
+
+	check := Check{
+		Name: "machineprovider-" + name,
+		Run: func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, ep.URL, http.NoBody)
+			if err != nil {
+				return err
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode >= http.StatusInternalServerError {
+				return fmt.Errorf("endpoint returned %s", resp.Status)
+			}
+			return nil
+		},
+	}
+	if ep.TimeoutSeconds > 0 {
+		check.Timeout = time.Duration(ep.TimeoutSeconds) * time.Second
+	}
+	return check, nil
+}
@@ -0,0 +1,133 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vitistack/common/pkg/clients/s3client/s3mock"
+	"github.com/vitistack/common/pkg/operator/crdcheck"
+	"github.com/vitistack/common/pkg/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func probe(t *testing.T, c Check) error {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/readyz", http.NoBody)
+	return c.Checker()(req)
+}
+
+func TestCheckerCachesResult(t *testing.T) {
+	calls := 0
+	fail := true
+	check := Check{
+		Name: "flaky",
+		Run: func(context.Context) error {
+			calls++
+			if fail {
+				return errors.New("down")
+			}
+			return nil
+		},
+		CacheTTL: time.Hour,
+	}
+	checker := check.Checker()
+	req := httptest.NewRequest(http.MethodGet, "/readyz", http.NoBody)
+
+	if err := checker(req); err == nil {
+		t.Fatalf("expected failure")
+	}
+	fail = false
+	if err := checker(req); err == nil || calls != 1 {
+		t.Fatalf("expected cached failure after 1 call, got err=%v calls=%d", err, calls)
+	}
+}
+
+func TestCheckerTimeout(t *testing.T) {
+	check := Check{
+		Name:    "slow",
+		Run:     func(context.Context) error { time.Sleep(time.Second); return nil },
+		Timeout: 10 * time.Millisecond,
+	}
+	if err := probe(t, check); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestCRDs(t *testing.T) {
+	dc := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}}
+	dc.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "vitistack.io/v1alpha1",
+		APIResources: []metav1.APIResource{{Name: "machines", Kind: "Machine"}},
+	}}
+	machines := crdcheck.Ref{Group: "vitistack.io", Version: "v1alpha1", Resource: "machines"}
+	if err := probe(t, CRDs(dc, machines)); err != nil {
+		t.Fatalf("CRDs() unexpected error: %v", err)
+	}
+	clusters := crdcheck.Ref{Group: "vitistack.io", Version: "v1alpha1", Resource: "kubernetesclusters"}
+	if err := probe(t, CRDs(dc, machines, clusters)); err == nil {
+		t.Fatalf("CRDs() expected error for missing kubernetesclusters")
+	}
+}
+
+func TestS3(t *testing.T) {
+	if err := probe(t, S3(s3mock.NewMockS3Client())); err != nil {
+		t.Fatalf("S3() unexpected error: %v", err)
+	}
+}
+
+func TestMachineProvider(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	check, err := MachineProvider("kubevirt", v1alpha1.ProviderEndpoint{URL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check.CacheTTL = -1
+	if err := probe(t, check); err != nil {
+		t.Fatalf("MachineProvider() unexpected error: %v", err)
+	}
+	status = http.StatusServiceUnavailable
+	if err := probe(t, check); err == nil {
+		t.Fatalf("MachineProvider() expected error for 503")
+	}
+
+	if _, err := MachineProvider("empty", v1alpha1.ProviderEndpoint{}); err == nil {
+		t.Fatalf("MachineProvider() expected error without URL")
+	}
+}
+
+func TestAPIServerHonoursTimeout(t *testing.T) {
+	cancelled := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		close(cancelled)
+	}))
+	defer srv.Close()
+
+	dc, err := discovery.NewDiscoveryClientForConfig(&rest.Config{Host: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := APIServer(dc)
+	check.Timeout = 50 * time.Millisecond
+	if err := probe(t, check); err == nil {
+		t.Fatalf("APIServer() expected error for a hanging API server")
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the /version request was not cancelled after the timeout")
+	}
+}
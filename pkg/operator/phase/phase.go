@@ -0,0 +1,236 @@
+package phase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/vitistack/common/pkg/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Object is a resource with a status phase, e.g. *v1alpha1.Machine.
+type Object interface {
+	client.Object
+	GetPhase() string
+	SetPhase(phase, message string)
+	// GetPhaseTransition and SetPhaseTransition store the reason and time of the last phase
+	// change in the object's status.
+	GetPhaseTransition() (reason string, at metav1.Time)
+	SetPhaseTransition(reason string, at metav1.Time)
+}
+
+// Transition describes a single phase change.
+type Transition struct {
+	Kind    string
+	From    string
+	To      string
+	Reason  string
+	Message string
+	Time    metav1.Time
+}
+
+// Hook runs when an object leaves or enters a phase.
+type Hook func(ctx context.Context, obj Object, t Transition) error
+
+// TransitionError is returned when a transition is not allowed.
+type TransitionError struct {
+	Kind    string
+	From    string
+	To      string
+	Allowed []string
+}
+
+func (e *TransitionError) Error() string {
+	from := e.From
+	if from == "" {
+		from = "<none>"
+	}
+	return fmt.Sprintf("%s: illegal phase transition %s -> %s (allowed: %v)", e.Kind, from, e.To, e.Allowed)
+}
+
+// StateMachine declares the allowed phase transitions for one kind and applies them.
+// The empty phase "" is the state of a newly created object.
+//
+// Example:
+//
+//	sm := phase.NewMachineStateMachine().
+//		OnEnter(v1alpha1.MachinePhaseTerminating, startTeardown)
+//	if _, err := sm.Transition(ctx, machine, v1alpha1.MachinePhaseRunning, "VMStarted", "vm is running"); err != nil {
+//		return reconcileutil.ResultFor(reconcileutil.Terminal(err))
+//	}
+//	return ctrl.Result{}, r.Status().Update(ctx, machine)
+type StateMachine struct {
+	Kind        string
+	transitions map[string][]string
+	onEnter     map[string][]Hook
+	onLeave     map[string][]Hook
+}
+
+// New creates a StateMachine for kind with the given allowed transitions (from -> to).
+func New(kind string, transitions map[string][]string) *StateMachine {
+	return &StateMachine{
+		Kind:        kind,
+		transitions: transitions,
+		onEnter:     map[string][]Hook{},
+		onLeave:     map[string][]Hook{},
+	}
+}
+
+// NewMachineStateMachine returns the state machine for Machine phases.
+func NewMachineStateMachine() *StateMachine {
+	return New("Machine", map[string][]string{
+		"": {v1alpha1.MachinePhasePending},
+		v1alpha1.MachinePhasePending: {
+			v1alpha1.MachinePhaseCreating, v1alpha1.MachinePhaseTerminating, v1alpha1.MachinePhaseFailed,
+		},
+		v1alpha1.MachinePhaseCreating: {
+			v1alpha1.MachinePhaseRunning, v1alpha1.MachinePhaseTerminating, v1alpha1.MachinePhaseFailed,
+		},
+		v1alpha1.MachinePhaseRunning: {
+			v1alpha1.MachinePhaseStopping, v1alpha1.MachinePhaseTerminating, v1alpha1.MachinePhaseFailed,
+		},
+		v1alpha1.MachinePhaseStopping: {
+			v1alpha1.MachinePhaseStopped, v1alpha1.MachinePhaseTerminating, v1alpha1.MachinePhaseFailed,
+		},
+		v1alpha1.MachinePhaseStopped: {
+			v1alpha1.MachinePhasePending, v1alpha1.MachinePhaseRunning, v1alpha1.MachinePhaseTerminating, v1alpha1.MachinePhaseFailed,
+		},
+		v1alpha1.MachinePhaseTerminating: {v1alpha1.MachinePhaseTerminated, v1alpha1.MachinePhaseFailed},
+		v1alpha1.MachinePhaseTerminated:  {},
+		v1alpha1.MachinePhaseFailed:      {v1alpha1.MachinePhasePending, v1alpha1.MachinePhaseTerminating},
+	})
+}
+
+// NewKubernetesClusterStateMachine returns the state machine for KubernetesCluster phases.
+// Deleted is terminal; a failed deletion moves Deleting -> Failed and is retried with
+// Failed -> Deleting.
+func NewKubernetesClusterStateMachine() *StateMachine {
+	return New("KubernetesCluster", map[string][]string{
+		"": {v1alpha1.KubernetesClusterPhaseProvisioning},
+		v1alpha1.KubernetesClusterPhaseProvisioning: {
+			v1alpha1.KubernetesClusterPhaseRunning, v1alpha1.KubernetesClusterPhaseDeleting, v1alpha1.KubernetesClusterPhaseFailed,
+		},
+		v1alpha1.KubernetesClusterPhaseRunning: {
+			v1alpha1.KubernetesClusterPhaseUpdating, v1alpha1.KubernetesClusterPhaseDeleting, v1alpha1.KubernetesClusterPhaseFailed,
+		},
+		v1alpha1.KubernetesClusterPhaseUpdating: {
+			v1alpha1.KubernetesClusterPhaseRunning, v1alpha1.KubernetesClusterPhaseDeleting, v1alpha1.KubernetesClusterPhaseFailed,
+		},
+		v1alpha1.KubernetesClusterPhaseDeleting: {v1alpha1.KubernetesClusterPhaseDeleted, v1alpha1.KubernetesClusterPhaseFailed},
+		v1alpha1.KubernetesClusterPhaseDeleted:  {},
+		v1alpha1.KubernetesClusterPhaseFailed: {
+			v1alpha1.KubernetesClusterPhaseProvisioning, v1alpha1.KubernetesClusterPhaseUpdating, v1alpha1.KubernetesClusterPhaseDeleting,
+		},
+	})
+}
+
+// NewClusterStorageStateMachine returns the state machine for ClusterStorage phases.
+func NewClusterStorageStateMachine() *StateMachine {
+	return New("ClusterStorage", map[string][]string{
+		"":                                       {v1alpha1.ClusterStoragePhasePending},
+		v1alpha1.ClusterStoragePhasePending:      {v1alpha1.ClusterStoragePhaseInitializing, v1alpha1.ClusterStoragePhaseFailed},
+		v1alpha1.ClusterStoragePhaseInitializing: {v1alpha1.ClusterStoragePhaseDeploying, v1alpha1.ClusterStoragePhaseFailed},
+		v1alpha1.ClusterStoragePhaseDeploying:    {v1alpha1.ClusterStoragePhaseReady, v1alpha1.ClusterStoragePhaseFailed},
+		v1alpha1.ClusterStoragePhaseReady:        {v1alpha1.ClusterStoragePhaseDeploying, v1alpha1.ClusterStoragePhaseFailed},
+		v1alpha1.ClusterStoragePhaseFailed: {
+			v1alpha1.ClusterStoragePhasePending, v1alpha1.ClusterStoragePhaseInitializing, v1alpha1.ClusterStoragePhaseDeploying,
+		},
+	})
+}
+
+// OnEnter registers a hook that runs after an object has entered phase.
+func (m *StateMachine) OnEnter(phase string, h Hook) *StateMachine {
+	m.onEnter[phase] = append(m.onEnter[phase], h)
+	return m
+}
+
+// OnLeave registers a hook that runs before an object leaves phase.
+func (m *StateMachine) OnLeave(phase string, h Hook) *StateMachine {
+	m.onLeave[phase] = append(m.onLeave[phase], h)
+	return m
+}
+
+// Phases returns every known phase, sorted, excluding the initial empty phase.
+func (m *StateMachine) Phases() []string {
+	var phases []string
+	for p := range m.transitions {
+		if p != "" {
+			phases = append(phases, p)
+		}
+	}
+	sort.Strings(phases)
+	return phases
+}
+
+// Allowed returns the phases reachable from the given phase.
+func (m *StateMachine) Allowed(from string) []string {
+	return slices.Clone(m.transitions[from])
+}
+
+// Validate returns a *TransitionError if from -> to is not allowed. Staying in the same
+// phase is always allowed.
+func (m *StateMachine) Validate(from, to string) error {
+	if from == to {
+		return nil
+	}
+	next, known := m.transitions[from]
+	if !known || !slices.Contains(next, to) {
+		return &TransitionError{Kind: m.Kind, From: from, To: to, Allowed: slices.Clone(next)}
+	}
+	return nil
+}
+
+// IsTerminal returns true when no transitions lead out of phase.
+func (m *StateMachine) IsTerminal(phase string) bool {
+	next, known := m.transitions[phase]
+	return known && len(next) == 0
+}
+
+// Transition moves obj to phase to. Leave hooks of the current phase run first and may veto
+// the change by returning an error, in which case obj is left untouched. The phase, message,
+// reason and transition time are then set on obj (the caller persists the status) and enter
+// hooks of the new phase run; their errors are returned but the phase change stands.
+//
+// Transitioning to the current phase only updates the message and runs no hooks.
+func (m *StateMachine) Transition(ctx context.Context, obj Object, to, reason, message string) (Transition, error) {
+	from := obj.GetPhase()
+	if err := m.Validate(from, to); err != nil {
+		return Transition{}, err
+	}
+	if message == "" {
+		message = reason
+	}
+	t := Transition{Kind: m.Kind, From: from, To: to, Reason: reason, Message: message, Time: metav1.Now()}
+	if from == to {
+		obj.SetPhase(to, message)
+		return t, nil
+	}
+
+	for _, h := range m.onLeave[from] {
+		if err := h(ctx, obj, t); err != nil {
+			return Transition{}, fmt.Errorf("%s: leaving phase %s: %w", m.Kind, from, err)
+		}
+	}
+	obj.SetPhase(to, message)
+	obj.SetPhaseTransition(reason, t.Time)
+
+	for _, h := range m.onEnter[to] {
+		if err := h(ctx, obj, t); err != nil {
+			return t, fmt.Errorf("%s: entering phase %s: %w", m.Kind, to, err)
+		}
+	}
+	return t, nil
+}
+
+// Last returns the most recent phase change recorded in obj's status. From and Message are
+// not stored, so they are empty. It returns false if obj has never changed phase.
+func (m *StateMachine) Last(obj Object) (Transition, bool) {
+	reason, at := obj.GetPhaseTransition()
+	if at.IsZero() {
+		return Transition{}, false
+	}
+	return Transition{Kind: m.Kind, To: obj.GetPhase(), Reason: reason, Time: at}, true
+}
@@ -0,0 +1,111 @@
+package phase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vitistack/common/pkg/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMachineTransitions(t *testing.T) {
+	sm := NewMachineStateMachine()
+	if err := sm.Validate(v1alpha1.MachinePhaseTerminated, v1alpha1.MachinePhaseRunning); err == nil {
+		t.Fatalf("Terminated -> Running must be rejected")
+	}
+	var te *TransitionError
+	if err := sm.Validate(v1alpha1.MachinePhasePending, v1alpha1.MachinePhaseStopped); !errors.As(err, &te) {
+		t.Fatalf("expected *TransitionError, got %v", err)
+	}
+	if err := sm.Validate("", v1alpha1.MachinePhasePending); err != nil {
+		t.Fatalf("new machine -> Pending: %v", err)
+	}
+	if !sm.IsTerminal(v1alpha1.MachinePhaseTerminated) || sm.IsTerminal(v1alpha1.MachinePhaseFailed) {
+		t.Fatalf("unexpected terminal phases")
+	}
+}
+
+func TestKubernetesClusterTransitions(t *testing.T) {
+	sm := NewKubernetesClusterStateMachine()
+	for _, tr := range [][2]string{
+		{v1alpha1.KubernetesClusterPhaseDeleting, v1alpha1.KubernetesClusterPhaseDeleted},
+		{v1alpha1.KubernetesClusterPhaseDeleting, v1alpha1.KubernetesClusterPhaseFailed},
+		{v1alpha1.KubernetesClusterPhaseFailed, v1alpha1.KubernetesClusterPhaseDeleting},
+	} {
+		if err := sm.Validate(tr[0], tr[1]); err != nil {
+			t.Errorf("%s -> %s: %v", tr[0], tr[1], err)
+		}
+	}
+	if err := sm.Validate(v1alpha1.KubernetesClusterPhaseDeleted, v1alpha1.KubernetesClusterPhaseRunning); err == nil {
+		t.Errorf("Deleted -> Running must be rejected")
+	}
+	if !sm.IsTerminal(v1alpha1.KubernetesClusterPhaseDeleted) || sm.IsTerminal(v1alpha1.KubernetesClusterPhaseDeleting) {
+		t.Errorf("unexpected terminal phases")
+	}
+}
+
+func TestTransitionRunsHooks(t *testing.T) {
+	m := &v1alpha1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "m1", UID: "uid-1"}}
+	m.Status.Phase = v1alpha1.MachinePhaseCreating
+
+	var calls []string
+	sm := NewMachineStateMachine().
+		OnLeave(v1alpha1.MachinePhaseCreating, func(_ context.Context, _ Object, tr Transition) error {
+			calls = append(calls, "leave "+tr.From)
+			return nil
+		}).
+		OnEnter(v1alpha1.MachinePhaseRunning, func(_ context.Context, obj Object, tr Transition) error {
+			calls = append(calls, "enter "+obj.GetPhase())
+			return nil
+		})
+
+	tr, err := sm.Transition(context.Background(), m, v1alpha1.MachinePhaseRunning, "VMStarted", "")
+	if err != nil {
+		t.Fatalf("Transition() unexpected error: %v", err)
+	}
+	if m.Status.Phase != v1alpha1.MachinePhaseRunning || m.Status.Message != "VMStarted" {
+		t.Fatalf("unexpected status %+v", m.Status)
+	}
+	if len(calls) != 2 || calls[0] != "leave Creating" || calls[1] != "enter Running" {
+		t.Fatalf("hooks = %v", calls)
+	}
+	if m.Status.PhaseReason != "VMStarted" || !m.Status.PhaseTransitionTime.Equal(&tr.Time) {
+		t.Fatalf("transition not recorded in status: %+v", m.Status)
+	}
+
+	// The transition survives a restart: a new state machine reads it back from the stored status.
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := &v1alpha1.Machine{}
+	if err := json.Unmarshal(data, stored); err != nil {
+		t.Fatal(err)
+	}
+	last, ok := NewMachineStateMachine().Last(stored)
+	if !ok || last.To != v1alpha1.MachinePhaseRunning || last.Reason != "VMStarted" || !last.Time.Equal(&metav1.Time{Time: tr.Time.Truncate(time.Second)}) {
+		t.Fatalf("Last() = %+v, %v", last, ok)
+	}
+	if _, ok := sm.Last(&v1alpha1.Machine{}); ok {
+		t.Fatalf("Last() of a new machine should report no transition")
+	}
+}
+
+func TestLeaveHookVetoesTransition(t *testing.T) {
+	cs := &v1alpha1.ClusterStorage{}
+	cs.Status.Phase = v1alpha1.ClusterStoragePhaseDeploying
+
+	sm := NewClusterStorageStateMachine().
+		OnLeave(v1alpha1.ClusterStoragePhaseDeploying, func(context.Context, Object, Transition) error {
+			return errors.New("deployment still rolling out")
+		})
+	if _, err := sm.Transition(context.Background(), cs, v1alpha1.ClusterStoragePhaseReady, "Deployed", ""); err == nil {
+		t.Fatalf("expected veto error")
+	}
+	if cs.Status.Phase != v1alpha1.ClusterStoragePhaseDeploying {
+		t.Fatalf("phase changed despite veto: %s", cs.Status.Phase)
+	}
+}
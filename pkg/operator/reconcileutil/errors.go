@@ -0,0 +1,172 @@
+package reconcileutil
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Terminal marks err as not worth retrying until the object changes. It is controller-runtime's
+// reconcile.TerminalError, so returning it directly from Reconcile behaves the same way.
+func Terminal(err error) error { return reconcile.TerminalError(err) }
+
+// IsTerminal reports whether err (or any error it wraps) is terminal.
+func IsTerminal(err error) bool { return err != nil && errors.Is(err, reconcile.TerminalError(nil)) }
+
+// TransientError marks a failure that is expected to go away on retry, e.g. a timeout
+// talking to a provider.
+type TransientError struct{ Err error }
+
+func (e *TransientError) Error() string { return "transient error: " + e.Err.Error() }
+func (e *TransientError) Unwrap() error { return e.Err }
+
+// Transient wraps err as a TransientError. Unclassified errors are treated as transient too.
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &TransientError{Err: err}
+}
+
+// IsTransient reports whether err is transient, i.e. not terminal, not a dependency wait and not a conflict.
+func IsTransient(err error) bool {
+	return err != nil && !IsTerminal(err) && !IsWaitingForDependency(err) && !IsConflict(err)
+}
+
+// DependencyError reports that reconciliation cannot proceed until another resource is ready.
+type DependencyError struct {
+	// Dependency names what is being waited for, e.g. "NetworkConfiguration default/web".
+	Dependency string
+	// After is how long to wait before checking again.
+	After time.Duration
+}
+
+func (e *DependencyError) Error() string {
+	return fmt.Sprintf("waiting for %s (recheck in %s)", e.Dependency, e.After)
+}
+
+// WaitingForDependency returns a DependencyError that requeues after the given duration.
+func WaitingForDependency(dependency string, after time.Duration) error {
+	return &DependencyError{Dependency: dependency, After: after}
+}
+
+// IsWaitingForDependency reports whether err is a DependencyError.
+func IsWaitingForDependency(err error) bool {
+	var de *DependencyError
+	return errors.As(err, &de)
+}
+
+// ConflictError marks an optimistic-lock conflict that should be retried immediately with
+// a fresh read of the object.
+type ConflictError struct{ Err error }
+
+func (e *ConflictError) Error() string { return "conflict: " + e.Err.Error() }
+func (e *ConflictError) Unwrap() error { return e.Err }
+
+// Conflict wraps err as a ConflictError.
+func Conflict(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ConflictError{Err: err}
+}
+
+// IsConflict reports whether err is a ConflictError or an API server 409 Conflict.
+func IsConflict(err error) bool {
+	var ce *ConflictError
+	return errors.As(err, &ce) || apierrors.IsConflict(err)
+}
+
+// ResultFor converts a classified error into the matching reconcile outcome:
+//   - nil: no requeue.
+//   - Terminal: no requeue; the error is returned so it is logged and counted in metrics.
+//   - WaitingForDependency: RequeueAfter the requested duration, no error.
+//   - Conflict (including API 409s): immediate requeue, no error.
+//   - Transient or unclassified: the error is returned so the controller's rate limiter backs off.
+//     Use RetryTracker.Result to back off with Backoff and a per-object attempt count instead.
+func ResultFor(err error) (ctrl.Result, error) {
+	if err == nil {
+		return NoRequeue(nil)
+	}
+	if IsTerminal(err) {
+		return NoRequeue(err)
+	}
+	var de *DependencyError
+	if errors.As(err, &de) {
+		return RequeueAfter(de.After, nil)
+	}
+	if IsConflict(err) {
+		return Requeue(nil)
+	}
+	return NoRequeue(err)
+}
+
+// MergeResults combines the results of several sub-steps by picking the earliest requeue.
+// An immediate requeue wins over any RequeueAfter.
+func MergeResults(results ...ctrl.Result) ctrl.Result {
+	var out ctrl.Result
+	for _, r := range results {
+		if r.Requeue && r.RequeueAfter == 0 { //nolint:staticcheck // immediate requeue is still honoured
+			return r
+		}
+		if r.RequeueAfter > 0 && (out.RequeueAfter == 0 || r.RequeueAfter < out.RequeueAfter) {
+			out = r
+		}
+	}
+	return out
+}
+
+// Aggregate collects the outcomes of independent sub-steps of one reconcile.
+//
+// Example:
+//
+//	var agg reconcileutil.Aggregate
+//	agg.Add(r.reconcileIPs(ctx, m))
+//	agg.AddError(r.reconcileDisks(ctx, m))
+//	return agg.Result()
+type Aggregate struct {
+	results []ctrl.Result
+	errs    []error
+}
+
+// Add records a sub-step that already produced a ctrl.Result.
+func (a *Aggregate) Add(res ctrl.Result, err error) {
+	a.results = append(a.results, res)
+	if err != nil {
+		a.errs = append(a.errs, err)
+	}
+}
+
+// AddError records a sub-step by classifying its error with ResultFor.
+func (a *Aggregate) AddError(err error) { a.Add(ResultFor(err)) }
+
+// Result returns the merged outcome. Errors are joined; the joined error is only terminal when
+// every collected error is terminal, so one terminal sub-step does not suppress retries of the others.
+func (a *Aggregate) Result() (ctrl.Result, error) {
+	if len(a.errs) == 0 {
+		return MergeResults(a.results...), nil
+	}
+	allTerminal := true
+	for _, err := range a.errs {
+		if !IsTerminal(err) {
+			allTerminal = false
+			break
+		}
+	}
+	if allTerminal {
+		return NoRequeue(errors.Join(a.errs...))
+	}
+	errs := make([]error, 0, len(a.errs))
+	for _, err := range a.errs {
+		if IsTerminal(err) {
+			// Keep the message but drop the terminal marker.
+			err = errors.New(err.Error())
+		}
+		errs = append(errs, err)
+	}
+	return NoRequeue(errors.Join(errs...))
+}
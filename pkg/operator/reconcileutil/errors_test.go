@@ -0,0 +1,83 @@
+package reconcileutil
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestResultFor(t *testing.T) {
+	boom := errors.New("boom")
+	apiConflict := apierrors.NewConflict(schema.GroupResource{Resource: "machines"}, "m1", boom)
+
+	tests := []struct {
+		name      string
+		err       error
+		wantAfter time.Duration
+		wantNow   bool
+		wantErr   bool
+	}{
+		{name: "nil", err: nil},
+		{name: "terminal", err: Terminal(boom), wantErr: true},
+		{name: "wrapped terminal", err: fmt.Errorf("reconcile: %w", Terminal(boom)), wantErr: true},
+		{name: "dependency", err: WaitingForDependency("NetworkConfiguration default/web", 15*time.Second), wantAfter: 15 * time.Second},
+		{name: "conflict", err: Conflict(boom), wantNow: true},
+		{name: "api conflict", err: apiConflict, wantNow: true},
+		{name: "transient", err: Transient(boom), wantErr: true},
+		{name: "unclassified", err: boom, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := ResultFor(tt.err)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResultFor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if res.RequeueAfter != tt.wantAfter {
+				t.Errorf("RequeueAfter = %s, want %s", res.RequeueAfter, tt.wantAfter)
+			}
+			if res.Requeue != tt.wantNow { //nolint:staticcheck // checking the immediate requeue flag
+				t.Errorf("Requeue = %v, want %v", res.Requeue, tt.wantNow) //nolint:staticcheck
+			}
+		})
+	}
+}
+
+func TestMergeResultsPicksEarliest(t *testing.T) {
+	got := MergeResults(ctrl.Result{}, ctrl.Result{RequeueAfter: time.Minute}, ctrl.Result{RequeueAfter: 5 * time.Second})
+	if got.RequeueAfter != 5*time.Second {
+		t.Fatalf("MergeResults() = %+v, want RequeueAfter 5s", got)
+	}
+	now, _ := Requeue(nil)
+	got = MergeResults(ctrl.Result{RequeueAfter: time.Second}, now)
+	if !got.Requeue || got.RequeueAfter != 0 { //nolint:staticcheck // immediate requeue must win
+		t.Fatalf("MergeResults() = %+v, want immediate requeue", got)
+	}
+}
+
+func TestAggregateDoesNotLetTerminalSuppressRetries(t *testing.T) {
+	var agg Aggregate
+	agg.AddError(Terminal(errors.New("bad spec")))
+	agg.AddError(WaitingForDependency("disk", 30*time.Second))
+	agg.AddError(errors.New("timeout"))
+
+	res, err := agg.Result()
+	if err == nil || IsTerminal(err) {
+		t.Fatalf("Aggregate.Result() error = %v, want non-terminal error", err)
+	}
+	if res.RequeueAfter != 0 {
+		t.Fatalf("Aggregate.Result() = %+v, want no explicit requeue when an error is returned", res)
+	}
+
+	agg = Aggregate{}
+	agg.AddError(WaitingForDependency("disk", 30*time.Second))
+	agg.AddError(WaitingForDependency("ip", 10*time.Second))
+	res, err = agg.Result()
+	if err != nil || res.RequeueAfter != 10*time.Second {
+		t.Fatalf("Aggregate.Result() = %+v, %v; want RequeueAfter 10s", res, err)
+	}
+}
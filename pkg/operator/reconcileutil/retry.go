@@ -0,0 +1,177 @@
+package reconcileutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vitistack/common/pkg/loggers/vlog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ErrRetriesExhausted is wrapped in the terminal error returned once MaxAttempts is reached.
+var ErrRetriesExhausted = errors.New("retries exhausted")
+
+// RetryStore persists the number of consecutive failed attempts per object.
+type RetryStore interface {
+	Get(obj client.Object) int
+	Set(obj client.Object, n int)
+	// Forget drops the count for obj, releasing anything kept for it.
+	Forget(obj client.Object)
+}
+
+// RetryCounter is implemented by objects that keep a retry count in their status,
+// such as *v1alpha1.NetworkNamespace and *v1alpha1.ControlPlaneVirtualSharedIP.
+type RetryCounter interface {
+	GetRetryCount() int
+	SetRetryCount(int)
+}
+
+// StatusRetryStore keeps the count in the object's status through RetryCounter.
+// The caller is responsible for writing the status back to the API server.
+// Objects that do not implement RetryCounter always report 0.
+type StatusRetryStore struct{}
+
+func (StatusRetryStore) Get(obj client.Object) int {
+	if rc, ok := obj.(RetryCounter); ok {
+		return rc.GetRetryCount()
+	}
+	return 0
+}
+
+func (StatusRetryStore) Set(obj client.Object, n int) {
+	if rc, ok := obj.(RetryCounter); ok {
+		rc.SetRetryCount(n)
+	}
+}
+
+func (s StatusRetryStore) Forget(obj client.Object) { s.Set(obj, 0) }
+
+// MemoryRetryStore keeps counts in memory keyed by object UID (or namespace/name when the
+// UID is empty). Counts are lost on restart. Entries are removed by Forget, which
+// RetryTracker.Result calls on success, on terminal failure and when the object is gone.
+type MemoryRetryStore struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// NewMemoryRetryStore creates an empty in-memory store.
+func NewMemoryRetryStore() *MemoryRetryStore {
+	return &MemoryRetryStore{counts: map[string]int{}}
+}
+
+func (m *MemoryRetryStore) Get(obj client.Object) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counts[retryKey(obj)]
+}
+
+func (m *MemoryRetryStore) Set(obj client.Object, n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counts == nil {
+		m.counts = map[string]int{}
+	}
+	if n == 0 {
+		delete(m.counts, retryKey(obj))
+		return
+	}
+	m.counts[retryKey(obj)] = n
+}
+
+func (m *MemoryRetryStore) Forget(obj client.Object) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.counts, retryKey(obj))
+}
+
+func retryKey(obj client.Object) string {
+	if uid := obj.GetUID(); uid != "" {
+		return string(uid)
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// RetryTracker counts consecutive failures per object, feeds the count into Backoff and
+// gives up with a terminal error after MaxAttempts.
+//
+// Example:
+//
+//	var retries = reconcileutil.NewRetryTracker(reconcileutil.StatusRetryStore{}, time.Second, 5*time.Minute, 10)
+//
+//	func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+//		...
+//		err := r.reconcileNamespace(ctx, nn)
+//		res, rerr := retries.Result(ctx, nn, err)
+//		if uerr := r.Status().Update(ctx, nn); uerr != nil {
+//			return reconcileutil.Requeue(uerr)
+//		}
+//		return res, rerr
+//	}
+type RetryTracker struct {
+	Store RetryStore
+	// Base and MaxDelay are passed to Backoff.
+	Base     time.Duration
+	MaxDelay time.Duration
+	// MaxAttempts caps the number of consecutive failures; 0 means unlimited.
+	MaxAttempts int
+}
+
+// NewRetryTracker creates a RetryTracker. A nil store defaults to a MemoryRetryStore.
+func NewRetryTracker(store RetryStore, base, maxDelay time.Duration, maxAttempts int) *RetryTracker {
+	if store == nil {
+		store = NewMemoryRetryStore()
+	}
+	return &RetryTracker{Store: store, Base: base, MaxDelay: maxDelay, MaxAttempts: maxAttempts}
+}
+
+// Attempts returns the number of consecutive failures recorded for obj.
+func (t *RetryTracker) Attempts(obj client.Object) int { return t.Store.Get(obj) }
+
+// Forget clears the count for obj. Result calls it on success, on terminal failure and when
+// obj no longer exists; call it yourself when obj is deleted outside of Result.
+func (t *RetryTracker) Forget(obj client.Object) { t.Store.Forget(obj) }
+
+// Result records the outcome of a reconcile attempt and returns the matching ctrl.Result.
+//   - err == nil forgets the count and does not requeue.
+//   - A NotFound error means obj is gone: the count is forgotten and the request is dropped.
+//   - Otherwise the count is incremented and the request is requeued after Backoff(count-1).
+//     The error is logged through the logger in ctx rather than returned, since
+//     controller-runtime would otherwise ignore RequeueAfter and apply its own rate limiter.
+//   - Once MaxAttempts is reached a terminal error wrapping ErrRetriesExhausted and err is returned,
+//     which stops requeuing until the object changes. The count is forgotten, so the next change
+//     starts with a fresh budget.
+//   - Terminal, WaitingForDependency and Conflict errors are handled by ResultFor; terminal
+//     errors also forget the count.
+func (t *RetryTracker) Result(ctx context.Context, obj client.Object, err error) (ctrl.Result, error) {
+	if err == nil || IsTerminal(err) {
+		t.Forget(obj)
+		return ResultFor(err)
+	}
+	if apierrors.IsNotFound(err) {
+		t.Forget(obj)
+		return NoRequeue(nil)
+	}
+	if IsWaitingForDependency(err) || IsConflict(err) {
+		return ResultFor(err)
+	}
+	n := t.Store.Get(obj) + 1
+	if t.MaxAttempts > 0 && n >= t.MaxAttempts {
+		t.Forget(obj)
+		return NoRequeue(reconcile.TerminalError(fmt.Errorf("%w after %d attempts: %w", ErrRetriesExhausted, n, err)))
+	}
+	t.Store.Set(obj, n)
+	delay := Backoff(n-1, t.Base, t.MaxDelay)
+	vlog.FromContext(ctx).Warn("reconcile failed, retrying",
+		"namespace", obj.GetNamespace(),
+		"name", obj.GetName(),
+		"attempt", n,
+		"retryIn", delay.Round(time.Millisecond).String(),
+		"error", err)
+	return RequeueAfter(delay, nil)
+}
@@ -0,0 +1,69 @@
+package reconcileutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vitistack/common/pkg/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestRetryTrackerStatusStore(t *testing.T) {
+	ctx := context.Background()
+	nn := &v1alpha1.NetworkNamespace{ObjectMeta: metav1.ObjectMeta{Name: "nn", Namespace: "default"}}
+	tracker := NewRetryTracker(StatusRetryStore{}, 10*time.Millisecond, 100*time.Millisecond, 3)
+	boom := errors.New("boom")
+
+	for i := 1; i <= 2; i++ {
+		res, err := tracker.Result(ctx, nn, boom)
+		if err != nil || res.RequeueAfter > 100*time.Millisecond {
+			t.Fatalf("attempt %d: unexpected result %+v, %v", i, res, err)
+		}
+		if nn.Status.RetryCount != i {
+			t.Fatalf("attempt %d: RetryCount = %d", i, nn.Status.RetryCount)
+		}
+	}
+
+	_, err := tracker.Result(ctx, nn, boom)
+	if !errors.Is(err, ErrRetriesExhausted) || !errors.Is(err, reconcile.TerminalError(nil)) || !errors.Is(err, boom) {
+		t.Fatalf("expected terminal exhausted error, got %v", err)
+	}
+	if nn.Status.RetryCount != 0 {
+		t.Fatalf("giving up should reset RetryCount so a fixed object starts over, got %d", nn.Status.RetryCount)
+	}
+
+	if _, err := tracker.Result(ctx, nn, boom); err != nil || nn.Status.RetryCount != 1 {
+		t.Fatalf("after giving up the next failure should be attempt 1, got %d, %v", nn.Status.RetryCount, err)
+	}
+	if _, err := tracker.Result(ctx, nn, nil); err != nil || nn.Status.RetryCount != 0 {
+		t.Fatalf("success should reset RetryCount, got %d, %v", nn.Status.RetryCount, err)
+	}
+}
+
+func TestRetryTrackerMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	obj := &v1alpha1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "m", Namespace: "default", UID: "uid-1"}}
+	tracker := NewRetryTracker(nil, time.Millisecond, time.Millisecond, 0)
+	for range 5 {
+		if _, err := tracker.Result(ctx, obj, errors.New("boom")); err != nil {
+			t.Fatalf("unlimited tracker should never return a terminal error: %v", err)
+		}
+	}
+	if got := tracker.Attempts(obj); got != 5 {
+		t.Fatalf("Attempts() = %d, want 5", got)
+	}
+
+	// A NotFound error means the object was deleted; its entry must not leak.
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "vitistack.io", Resource: "machines"}, "m")
+	if res, err := tracker.Result(ctx, obj, notFound); err != nil || res.RequeueAfter != 0 {
+		t.Fatalf("Result(NotFound) = %+v, %v, want no requeue", res, err)
+	}
+	if n := len(tracker.Store.(*MemoryRetryStore).counts); n != 0 {
+		t.Fatalf("store still holds %d entries after the object was deleted", n)
+	}
+}
@@ -1,18 +1,106 @@
 package runtime
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/vitistack/common/pkg/loggers/vlog"
+	"github.com/vitistack/common/pkg/operator/crdcheck"
+	"github.com/vitistack/common/pkg/operator/env"
+	"github.com/vitistack/common/pkg/operator/health"
+	"github.com/vitistack/common/pkg/v1alpha1"
 	krt "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
-// ManagerOptions wraps a subset of ctrl.Options for convenience.
+// Environment variables read by ManagerOptionsFromEnv.
+const (
+	EnvMetricsBindAddress      = "METRICS_BIND_ADDRESS"
+	EnvHealthProbeBindAddress  = "HEALTH_PROBE_BIND_ADDRESS"
+	EnvPprofBindAddress        = "PPROF_BIND_ADDRESS"
+	EnvWebhookPort             = "WEBHOOK_PORT"
+	EnvWebhookCertDir          = "WEBHOOK_CERT_DIR"
+	EnvLeaderElect             = "LEADER_ELECT"
+	EnvLeaderElectionID        = "LEADER_ELECTION_ID"
+	EnvLeaderElectionNamespace = "LEADER_ELECTION_NAMESPACE"
+	EnvWatchNamespaces         = "WATCH_NAMESPACES"
+	EnvSyncPeriod              = "SYNC_PERIOD"
+	EnvGracefulShutdownTimeout = "GRACEFUL_SHUTDOWN_TIMEOUT"
+)
+
+// Defaults applied by ManagerOptionsFromEnv.
+const (
+	DefaultMetricsBindAddress     = ":8080"
+	DefaultHealthProbeBindAddress = ":8081"
+	DefaultWebhookPort            = 9443
+)
+
+// ManagerOptions wraps a subset of ctrl.Options for convenience. Zero values keep the
+// controller-runtime defaults. The env tags are read by ManagerOptionsFromEnv.
 type ManagerOptions struct {
-	Scheme         *krt.Scheme
-	LeaderElection bool
+	// Scheme defaults to client-go's scheme. The vitistack v1alpha1 types are always added, to
+	// the given Scheme itself when one is set.
+	Scheme *krt.Scheme
+
+	LeaderElection          bool   `env:"LEADER_ELECT"`
+	LeaderElectionID        string `env:"LEADER_ELECTION_ID"`
+	LeaderElectionNamespace string `env:"LEADER_ELECTION_NAMESPACE"`
+
+	// MetricsBindAddress is the metrics listen address; "0" disables metrics.
+	MetricsBindAddress string `env:"METRICS_BIND_ADDRESS" default:":8080"`
+	// HealthProbeBindAddress is the healthz/readyz listen address; empty disables the probe server.
+	HealthProbeBindAddress string `env:"HEALTH_PROBE_BIND_ADDRESS" default:":8081"`
+	// PprofBindAddress enables pprof when set, e.g. ":6060".
+	PprofBindAddress string `env:"PPROF_BIND_ADDRESS"`
+
+	WebhookPort    int    `env:"WEBHOOK_PORT" default:"9443"`
+	WebhookCertDir string `env:"WEBHOOK_CERT_DIR"`
+
+	// CacheNamespaces restricts the cache (and thereby watches) to these namespaces. Empty means all.
+	CacheNamespaces []string `env:"WATCH_NAMESPACES"`
+	// SyncPeriod is the minimum resync interval for watched objects.
+	SyncPeriod time.Duration `env:"SYNC_PERIOD"`
+	// GracefulShutdownTimeout bounds how long runnables get to stop.
+	GracefulShutdownTimeout time.Duration `env:"GRACEFUL_SHUTDOWN_TIMEOUT"`
+
+	// HealthChecks are added to /healthz next to the built-in "ping".
+	HealthChecks []health.Check
+	// ReadyChecks are added to /readyz next to the built-in "apiserver" and "cache-synced" checks,
+	// e.g. health.CRDs, health.S3 or health.MachineProvider.
+	ReadyChecks []health.Check
+	// RequiredCRDs are waited for in the background once the manager starts; /readyz reports
+	// "crds" as failing until they are all served.
+	RequiredCRDs []crdcheck.Ref
+}
+
+// ManagerOptionsFromEnv reads ManagerOptions from the environment (see the Env* constants).
+// Invalid values, e.g. LEADER_ELECT=ture, are not replaced by defaults: they are all returned
+// in an *env.LoadError.
+//
+// Example:
+//
+//	dotenv.LoadDotEnv()
+//	o, err := runtime.ManagerOptionsFromEnv()
+//	if err != nil {
+//		return err
+//	}
+//	mgr, err := runtime.NewManagerWithDefaults(ctrl.GetConfigOrDie(), o)
+func ManagerOptionsFromEnv() (ManagerOptions, error) {
+	var o ManagerOptions
+	if err := env.Load(&o); err != nil {
+		return ManagerOptions{}, err
+	}
+	return o, nil
 }
 
 // NewManagerWithDefaults sets up vlog as the logger and builds a controller-runtime manager
@@ -21,16 +109,107 @@ func NewManagerWithDefaults(cfg *rest.Config, o ManagerOptions) (ctrl.Manager, e
 	// Ensure vlog is the logger for controller-runtime
 	ctrl.SetLogger(vlog.Logr())
 
-	opts := ctrl.Options{
-		Scheme:         o.Scheme,
-		LeaderElection: o.LeaderElection,
+	opts, err := o.ctrlOptions()
+	if err != nil {
+		return nil, err
 	}
 	mgr, err := ctrl.NewManager(cfg, opts)
 	if err != nil {
 		return nil, err
 	}
-	// Register default health and ready checks
-	_ = mgr.AddHealthzCheck("ping", func(_ *http.Request) error { return nil })
-	_ = mgr.AddReadyzCheck("ping", func(_ *http.Request) error { return nil })
+	if err := addChecks(cfg, mgr, &o); err != nil {
+		return nil, err
+	}
 	return mgr, nil
 }
+
+// addChecks registers the default and user-supplied health and ready checks.
+func addChecks(cfg *rest.Config, mgr ctrl.Manager, o *ManagerOptions) error {
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	// Liveness stays dependency-free so a lost dependency does not restart the pod
+	if err := mgr.AddHealthzCheck("ping", func(_ *http.Request) error { return nil }); err != nil {
+		return err
+	}
+	for _, c := range o.HealthChecks {
+		if err := mgr.AddHealthzCheck(c.Name, c.Checker()); err != nil {
+			return fmt.Errorf("failed to add health check %s: %w", c.Name, err)
+		}
+	}
+	ready := append([]health.Check{health.APIServer(dc), health.CacheSynced(mgr.GetCache())}, o.ReadyChecks...)
+	for _, c := range ready {
+		if err := mgr.AddReadyzCheck(c.Name, c.Checker()); err != nil {
+			return fmt.Errorf("failed to add ready check %s: %w", c.Name, err)
+		}
+	}
+	if len(o.RequiredCRDs) > 0 {
+		w := crdcheck.NewWaiter(dc, o.RequiredCRDs...)
+		if err := mgr.AddReadyzCheck("crds", w.Ready); err != nil {
+			return fmt.Errorf("failed to add ready check crds: %w", err)
+		}
+		if err := mgr.Add(crdWaiter{w}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// crdWaiter runs a crdcheck.Waiter on every replica, not only the leader.
+type crdWaiter struct{ w *crdcheck.Waiter }
+
+func (c crdWaiter) Start(ctx context.Context) error {
+	if err := c.w.Wait(ctx, 0); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+func (crdWaiter) NeedLeaderElection() bool { return false }
+
+var _ manager.LeaderElectionRunnable = crdWaiter{}
+
+func (o *ManagerOptions) ctrlOptions() (ctrl.Options, error) {
+	if o.LeaderElection && o.LeaderElectionID == "" {
+		return ctrl.Options{}, errors.New("leader election requires a leader election ID")
+	}
+
+	scheme := o.Scheme
+	if scheme == nil {
+		scheme = krt.NewScheme()
+		if err := clientgoscheme.AddToScheme(scheme); err != nil {
+			return ctrl.Options{}, fmt.Errorf("failed to register client-go scheme: %w", err)
+		}
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		return ctrl.Options{}, fmt.Errorf("failed to register vitistack v1alpha1 scheme: %w", err)
+	}
+
+	opts := ctrl.Options{
+		Scheme:                  scheme,
+		LeaderElection:          o.LeaderElection,
+		LeaderElectionID:        o.LeaderElectionID,
+		LeaderElectionNamespace: o.LeaderElectionNamespace,
+		Metrics:                 metricsserver.Options{BindAddress: o.MetricsBindAddress},
+		HealthProbeBindAddress:  o.HealthProbeBindAddress,
+		PprofBindAddress:        o.PprofBindAddress,
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    o.WebhookPort,
+			CertDir: o.WebhookCertDir,
+		}),
+	}
+	if len(o.CacheNamespaces) > 0 {
+		opts.Cache.DefaultNamespaces = make(map[string]cache.Config, len(o.CacheNamespaces))
+		for _, ns := range o.CacheNamespaces {
+			opts.Cache.DefaultNamespaces[ns] = cache.Config{}
+		}
+	}
+	if o.SyncPeriod > 0 {
+		opts.Cache.SyncPeriod = &o.SyncPeriod
+	}
+	if o.GracefulShutdownTimeout > 0 {
+		opts.GracefulShutdownTimeout = &o.GracefulShutdownTimeout
+	}
+	return opts, nil
+}
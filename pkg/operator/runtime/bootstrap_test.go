@@ -0,0 +1,103 @@
+package runtime
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/vitistack/common/pkg/operator/env"
+	"github.com/vitistack/common/pkg/v1alpha1"
+)
+
+func TestManagerOptionsFromEnv(t *testing.T) {
+	t.Setenv(EnvLeaderElect, "true")
+	t.Setenv(EnvLeaderElectionID, "machine-operator.vitistack.io")
+	t.Setenv(EnvWatchNamespaces, "ns-a,ns-b")
+	t.Setenv(EnvSyncPeriod, "10m")
+	t.Setenv(EnvGracefulShutdownTimeout, "45s")
+
+	o, err := ManagerOptionsFromEnv()
+	if err != nil {
+		t.Fatalf("ManagerOptionsFromEnv() unexpected error: %v", err)
+	}
+	if o.MetricsBindAddress != DefaultMetricsBindAddress || o.WebhookPort != DefaultWebhookPort {
+		t.Fatalf("defaults not applied: %+v", o)
+	}
+
+	opts, err := o.ctrlOptions()
+	if err != nil {
+		t.Fatalf("ctrlOptions() unexpected error: %v", err)
+	}
+	if !opts.LeaderElection || opts.LeaderElectionID != "machine-operator.vitistack.io" {
+		t.Errorf("leader election not configured: %v %q", opts.LeaderElection, opts.LeaderElectionID)
+	}
+	if len(opts.Cache.DefaultNamespaces) != 2 {
+		t.Errorf("cache namespaces = %v", opts.Cache.DefaultNamespaces)
+	}
+	if opts.Cache.SyncPeriod == nil || *opts.Cache.SyncPeriod != 10*time.Minute {
+		t.Errorf("sync period = %v", opts.Cache.SyncPeriod)
+	}
+	if opts.GracefulShutdownTimeout == nil || *opts.GracefulShutdownTimeout != 45*time.Second {
+		t.Errorf("graceful shutdown timeout = %v", opts.GracefulShutdownTimeout)
+	}
+	if !opts.Scheme.Recognizes(v1alpha1.GroupVersion.WithKind("Machine")) {
+		t.Errorf("v1alpha1 scheme not registered")
+	}
+}
+
+func TestManagerOptionsFromEnv_InvalidValues(t *testing.T) {
+	t.Setenv(EnvLeaderElect, "ture")
+	t.Setenv(EnvWebhookPort, "94430x")
+	t.Setenv(EnvSyncPeriod, "10")
+
+	_, err := ManagerOptionsFromEnv()
+	var le *env.LoadError
+	if !errors.As(err, &le) {
+		t.Fatalf("ManagerOptionsFromEnv() error = %v, want *env.LoadError", err)
+	}
+	var keys []string
+	for _, fe := range le.Errors {
+		keys = append(keys, fe.Key)
+	}
+	if want := []string{EnvLeaderElect, EnvWebhookPort, EnvSyncPeriod}; !slices.Equal(keys, want) {
+		t.Errorf("invalid keys = %v, want %v", keys, want)
+	}
+}
+
+// The env tags on ManagerOptions must stay in sync with the exported constants.
+func TestManagerOptionsEnvTags(t *testing.T) {
+	var keys []string
+	defaults := map[string]string{}
+	for _, f := range env.Fields(ManagerOptions{}) {
+		keys = append(keys, f.Key)
+		if f.Default != "" {
+			defaults[f.Key] = f.Default
+		}
+	}
+	want := []string{
+		EnvLeaderElect, EnvLeaderElectionID, EnvLeaderElectionNamespace, EnvMetricsBindAddress,
+		EnvHealthProbeBindAddress, EnvPprofBindAddress, EnvWebhookPort, EnvWebhookCertDir,
+		EnvWatchNamespaces, EnvSyncPeriod, EnvGracefulShutdownTimeout,
+	}
+	if !slices.Equal(keys, want) {
+		t.Errorf("env keys = %v, want %v", keys, want)
+	}
+	wantDefaults := map[string]string{
+		EnvMetricsBindAddress:     DefaultMetricsBindAddress,
+		EnvHealthProbeBindAddress: DefaultHealthProbeBindAddress,
+		EnvWebhookPort:            strconv.Itoa(DefaultWebhookPort),
+	}
+	if !maps.Equal(defaults, wantDefaults) {
+		t.Errorf("env defaults = %v, want %v", defaults, wantDefaults)
+	}
+}
+
+func TestLeaderElectionRequiresID(t *testing.T) {
+	o := ManagerOptions{LeaderElection: true}
+	if _, err := o.ctrlOptions(); err == nil {
+		t.Fatalf("expected error without leader election ID")
+	}
+}
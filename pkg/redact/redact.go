@@ -0,0 +1,127 @@
+// Package redact hides sensitive values before they are printed. It depends only on the standard
+// library so that loggers can use it without pulling in Kubernetes types.
+package redact
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Redacted replaces sensitive values in printed output.
+const Redacted = "[REDACTED]"
+
+var disabled atomic.Bool
+
+// SetEnabled turns redaction on or off for the packages that print values (serialize, vlog,
+// config). It is on by default.
+func SetEnabled(enabled bool) { disabled.Store(!enabled) }
+
+// Enabled reports whether sensitive values are redacted.
+func Enabled() bool { return !disabled.Load() }
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string][][]string{}
+)
+
+func init() {
+	// Registered by name so this package does not depend on k8s.io/api.
+	register("k8s.io/api/core/v1.Secret", "data", "stringData")
+}
+
+// Register marks JSON paths (dot separated, relative to the type of sample) as sensitive
+// wherever a value of that type is printed, e.g.
+//
+//	redact.Register(corev1.Secret{}, "data", "stringData")
+func Register(sample any, paths ...string) {
+	t := reflect.TypeOf(sample)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return
+	}
+	register(typeKey(t), paths...)
+}
+
+func register(key string, paths ...string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, p := range paths {
+		registry[key] = append(registry[key], strings.Split(p, "."))
+	}
+}
+
+// registered returns the paths registered for t.
+func registered(t reflect.Type) [][]string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[typeKey(t)]
+}
+
+func typeKey(t reflect.Type) string {
+	if t.Name() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+var (
+	sensitiveWords = []string{
+		"password", "passwd", "secret", "token", "privatekey", "accesskey", "credential", "apikey",
+		"kubeconfig", "clientkey",
+	}
+	// Keys ending in these name or point to a secret rather than hold one, e.g. secretRef.
+	referenceSuffixes = []string{"ref", "refs", "name", "names", "namespace", "file", "path", "type", "id"}
+)
+
+// IsSensitiveKey reports whether a field or variable name looks like it holds a secret, e.g.
+// token, password, secretKey or S3_SECRET_KEY. Names of references such as secretRef,
+// secretName or TOKEN_FILE are not sensitive.
+func IsSensitiveKey(key string) bool {
+	k := strings.NewReplacer("_", "", "-", "", ".", "").Replace(strings.ToLower(key))
+	for _, s := range referenceSuffixes {
+		if strings.HasSuffix(k, s) {
+			return false
+		}
+	}
+	for _, w := range sensitiveWords {
+		if strings.Contains(k, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonName returns the JSON key of a struct field and whether it is inlined into its parent.
+func jsonName(sf reflect.StructField) (string, bool) {
+	tag := sf.Tag.Get("json")
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "-" && opts == "" {
+		return "-", false
+	}
+	if strings.Contains(opts, "inline") || (name == "" && sf.Anonymous) {
+		return "", true
+	}
+	if name == "" {
+		name = sf.Name
+	}
+	return name, false
+}
+
+// tagged reports whether a struct field is tagged as sensitive.
+func tagged(sf reflect.StructField) bool {
+	return sf.Tag.Get("redact") == "true" || sf.Tag.Get("secret") == "true"
+}
+
+// exempt reports whether a struct field opts out of the key heuristics.
+func exempt(sf reflect.StructField) bool { return sf.Tag.Get("redact") == "false" }
+
+const maxDepth = 32
+
+func appendPath(path []string, elems ...string) []string {
+	out := make([]string, 0, len(path)+len(elems))
+	return append(append(out, path...), elems...)
+}
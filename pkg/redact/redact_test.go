@@ -0,0 +1,129 @@
+package redact
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestIsSensitiveKey(t *testing.T) {
+	tests := map[string]bool{
+		"token":               true,
+		"password":            true,
+		"secretKey":           true,
+		"S3_SECRET_KEY":       true,
+		"apiKey":              true,
+		"kubeconfig":          true,
+		"name":                false,
+		"secretRef":           false,
+		"secretName":          false,
+		"kubeconfigSecretRef": false,
+		"S3_SECRET_KEY_FILE":  false,
+		"tokenType":           false,
+	}
+	for key, want := range tests {
+		if got := IsSensitiveKey(key); got != want {
+			t.Errorf("IsSensitiveKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+type testConfig struct {
+	Endpoint  string            `json:"endpoint"`
+	APIKey    string            `json:"key" redact:"true"`
+	Password  string            `json:"password"`
+	SecretKey string            `json:"userDataSecretKey" redact:"false"`
+	Port      int               `json:"port" secret:"true"`
+	Headers   map[string]string `json:"headers"`
+	Nested    []struct {
+		Token string
+	} `json:"nested"`
+	unexported string
+}
+
+type registeredType struct {
+	Payload map[string][]byte `json:"payload"`
+	Other   string            `json:"other"`
+}
+
+func TestValue(t *testing.T) {
+	Register(registeredType{}, "payload")
+
+	cfg := &testConfig{
+		Endpoint:   "https://s3",
+		APIKey:     "k",
+		Password:   "p",
+		SecretKey:  "userdata",
+		Port:       9000,
+		Headers:    map[string]string{"Authorization-Token": "t", "Accept": "json"},
+		unexported: "kept",
+	}
+	cfg.Nested = append(cfg.Nested, struct{ Token string }{"t"})
+
+	tests := []struct {
+		name  string
+		input any
+		want  any
+	}{
+		{
+			name:  "tags, heuristics and opt-out",
+			input: cfg,
+			want: &testConfig{
+				Endpoint:   "https://s3",
+				APIKey:     Redacted,
+				Password:   Redacted,
+				SecretKey:  "userdata",
+				Headers:    map[string]string{"Authorization-Token": Redacted, "Accept": "json"},
+				Nested:     []struct{ Token string }{{Redacted}},
+				unexported: "kept",
+			},
+		},
+		{
+			name:  "registered path",
+			input: registeredType{Payload: map[string][]byte{"a": []byte("b")}, Other: "o"},
+			want:  registeredType{Payload: map[string][]byte{"a": []byte(Redacted)}, Other: "o"},
+		},
+		{
+			name:  "generic map",
+			input: map[string]any{"user": "u", "credentials": map[string]any{"accessKey": "AK"}},
+			want:  map[string]any{"user": "u", "credentials": map[string]any{"accessKey": Redacted}},
+		},
+		{
+			name:  "empty values stay empty",
+			input: testConfig{Endpoint: "e"},
+			want:  testConfig{Endpoint: "e"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Value(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Value() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	if cfg.Password != "p" || cfg.Headers["Authorization-Token"] != "t" || cfg.Nested[0].Token != "t" {
+		t.Errorf("Value must not modify its input: %#v", cfg)
+	}
+}
+
+func TestValue_NothingSensitiveReturnsInput(t *testing.T) {
+	in := &testConfig{Endpoint: "e", Headers: map[string]string{"Accept": "json"}}
+	if got := Value(in); got != any(in) {
+		t.Errorf("Value() = %p, want the input %p", got, in)
+	}
+}
+
+func TestTree(t *testing.T) {
+	in := testConfig{Endpoint: "e", APIKey: "k", SecretKey: "userdata"}
+	b, _ := json.Marshal(in)
+	var tree map[string]any
+	_ = json.Unmarshal(b, &tree)
+
+	if !Tree(in, tree) {
+		t.Fatal("Tree() = false, want true")
+	}
+	if tree["key"] != Redacted || tree["endpoint"] != "e" || tree["userDataSecretKey"] != "userdata" {
+		t.Errorf("Tree() = %v", tree)
+	}
+}
@@ -0,0 +1,182 @@
+package redact
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Tree redacts tree, the generic JSON form of v (maps, slices and scalars as produced by
+// json.Unmarshal into an any), in place and reports whether anything changed. It applies the
+// same rules as Value and is meant for callers that already work on the JSON form, such as
+// manifest rendering and diffs.
+func Tree(v, tree any) bool {
+	w := &pathWalker{keep: map[string]bool{}}
+	w.walk(reflect.ValueOf(v), nil, 0)
+
+	changed := false
+	for _, p := range w.sensitive {
+		if setPath(tree, p) {
+			changed = true
+		}
+	}
+	if redactKeys(tree, nil, w.keep) {
+		changed = true
+	}
+	return changed
+}
+
+// pathWalker collects the JSON paths of tagged and registered fields from a Go value.
+type pathWalker struct {
+	sensitive [][]string
+	keep      map[string]bool
+}
+
+func (w *pathWalker) walk(rv reflect.Value, path []string, depth int) {
+	if depth > maxDepth {
+		return
+	}
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		for _, p := range registered(rv.Type()) {
+			w.sensitive = append(w.sensitive, appendPath(path, p...))
+		}
+
+		t := rv.Type()
+		for i := range t.NumField() {
+			sf := t.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			name, inline := jsonName(sf)
+			if name == "-" {
+				continue
+			}
+			fieldPath := path
+			if !inline {
+				fieldPath = appendPath(path, name)
+			}
+			switch {
+			case tagged(sf):
+				w.sensitive = append(w.sensitive, fieldPath)
+				continue
+			case exempt(sf):
+				w.keep[pathKey(fieldPath)] = true
+			}
+			w.walk(rv.Field(i), fieldPath, depth+1)
+		}
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := range rv.Len() {
+			w.walk(rv.Index(i), appendPath(path, strconv.Itoa(i)), depth+1)
+		}
+	case reflect.Map:
+		iter := rv.MapRange()
+		for iter.Next() {
+			w.walk(iter.Value(), appendPath(path, fmt.Sprint(iter.Key().Interface())), depth+1)
+		}
+	}
+}
+
+// setPath replaces the non-empty value at path in tree with Redacted.
+func setPath(tree any, path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+	switch node := tree.(type) {
+	case map[string]any:
+		child, ok := node[path[0]]
+		if !ok {
+			return false
+		}
+		if len(path) == 1 {
+			if isEmptyJSON(child) || child == Redacted {
+				return false
+			}
+			node[path[0]] = Redacted
+			return true
+		}
+		return setPath(child, path[1:])
+	case []any:
+		i, err := strconv.Atoi(path[0])
+		if err != nil || i < 0 || i >= len(node) {
+			return false
+		}
+		if len(path) == 1 {
+			if isEmptyJSON(node[i]) {
+				return false
+			}
+			node[i] = Redacted
+			return true
+		}
+		return setPath(node[i], path[1:])
+	}
+	return false
+}
+
+// redactKeys applies the IsSensitiveKey heuristic to string values below tree.
+func redactKeys(tree any, path []string, keep map[string]bool) bool {
+	changed := false
+	switch node := tree.(type) {
+	case map[string]any:
+		for k, v := range node {
+			p := appendPath(path, k)
+			if keep[pathKey(p)] {
+				continue
+			}
+			if s, ok := v.(string); ok {
+				if s != "" && s != Redacted && IsSensitiveKey(k) {
+					node[k] = Redacted
+					changed = true
+				}
+				continue
+			}
+			if redactKeys(v, p, keep) {
+				changed = true
+			}
+		}
+	case []any:
+		for i, v := range node {
+			if redactKeys(v, appendPath(path, strconv.Itoa(i)), keep) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// isEmptyJSON reports whether v holds only zero values, e.g. {"phase": "", "conditions": null}.
+func isEmptyJSON(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case bool:
+		return !t
+	case float64:
+		return t == 0
+	case []any:
+		return len(t) == 0
+	case map[string]any:
+		for _, item := range t {
+			if !isEmptyJSON(item) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func pathKey(path []string) string { return strings.Join(path, "\x00") }
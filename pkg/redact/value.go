@@ -0,0 +1,257 @@
+package redact
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Value returns v with sensitive values replaced by Redacted. Values are sensitive when their
+// struct field is tagged `redact:"true"` or `secret:"true"`, when their path is registered with
+// Register, or when their key matches IsSensitiveKey and holds a string. Tag a field
+// `redact:"false"` to exempt it from the key heuristics.
+//
+// The result is a copy of v with the same type, so it serializes with the same key names and
+// order; v is never modified. Strings and byte slices are replaced by Redacted, maps and lists
+// keep their keys and have each value redacted, and other values are cleared. When nothing is
+// sensitive v itself is returned. Value redacts regardless of Enabled.
+func Value(v any) any {
+	if v == nil {
+		return nil
+	}
+	out, changed := value(reflect.ValueOf(v), nil, true, 0)
+	if !changed {
+		return v
+	}
+	return out.Interface()
+}
+
+// value returns rv with the sensitive values below it redacted and reports whether anything
+// changed. pending holds the registered paths, relative to rv, that are still to be matched and
+// keys tells whether the key heuristics apply. Only the parts that change are copied.
+func value(rv reflect.Value, pending [][]string, keys bool, depth int) (reflect.Value, bool) {
+	if depth > maxDepth {
+		return rv, false
+	}
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return rv, false
+		}
+		elem, changed := value(rv.Elem(), pending, keys, depth+1)
+		if !changed {
+			return rv, false
+		}
+		out := reflect.New(rv.Type().Elem())
+		out.Elem().Set(elem)
+		return out, true
+	case reflect.Interface:
+		if rv.IsNil() {
+			return rv, false
+		}
+		elem, changed := value(rv.Elem(), pending, keys, depth+1)
+		if !changed {
+			return rv, false
+		}
+		out := reflect.New(rv.Type()).Elem()
+		out.Set(elem)
+		return out, true
+	case reflect.Struct:
+		return structValue(rv, append(pending, registered(rv.Type())...), keys, depth)
+	case reflect.Map:
+		return mapValue(rv, pending, keys, depth)
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv, false
+		}
+		var out reflect.Value
+		for i := range rv.Len() {
+			elem, changed := entry(rv.Index(i), strconv.Itoa(i), pending, keys, depth)
+			if !changed {
+				continue
+			}
+			if !out.IsValid() {
+				out = copyList(rv)
+			}
+			out.Index(i).Set(elem)
+		}
+		return orig(rv, out)
+	}
+	return rv, false
+}
+
+func structValue(rv reflect.Value, pending [][]string, keys bool, depth int) (reflect.Value, bool) {
+	var out reflect.Value
+	t := rv.Type()
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, inline := jsonName(sf)
+		if name == "-" {
+			continue
+		}
+		fieldKeys := keys && !exempt(sf)
+		var (
+			field   reflect.Value
+			changed bool
+		)
+		switch {
+		case tagged(sf):
+			field, changed = hide(rv.Field(i))
+		case inline:
+			field, changed = value(rv.Field(i), pending, fieldKeys, depth+1)
+		default:
+			field, changed = entry(rv.Field(i), name, pending, fieldKeys, depth)
+		}
+		if !changed {
+			continue
+		}
+		if !out.IsValid() {
+			out = reflect.New(t).Elem()
+			out.Set(rv)
+		}
+		out.Field(i).Set(field)
+	}
+	return orig(rv, out)
+}
+
+func mapValue(rv reflect.Value, pending [][]string, keys bool, depth int) (reflect.Value, bool) {
+	changes := map[int]reflect.Value{}
+	var entries []reflect.Value
+	iter := rv.MapRange()
+	for iter.Next() {
+		k := iter.Key()
+		entries = append(entries, k)
+		entryKeys := keys && k.Kind() == reflect.String
+		if elem, changed := entry(iter.Value(), fmt.Sprint(k.Interface()), pending, entryKeys, depth); changed {
+			changes[len(entries)-1] = elem
+		}
+	}
+	if len(changes) == 0 {
+		return rv, false
+	}
+	out := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+	for i, k := range entries {
+		if elem, ok := changes[i]; ok {
+			out.SetMapIndex(k, elem)
+		} else {
+			out.SetMapIndex(k, rv.MapIndex(k))
+		}
+	}
+	return out, true
+}
+
+// entry redacts the value stored under key in a struct, map or list.
+func entry(rv reflect.Value, key string, pending [][]string, keys bool, depth int) (reflect.Value, bool) {
+	rest, matched := advance(pending, key)
+	switch {
+	case matched:
+		return hide(rv)
+	case keys && holdsString(rv) && IsSensitiveKey(key):
+		return hide(rv)
+	}
+	return value(rv, rest, keys, depth+1)
+}
+
+// advance matches key against the pending paths. It returns the remainders of the paths that
+// continue below key and whether a path ends at key.
+func advance(pending [][]string, key string) (rest [][]string, matched bool) {
+	for _, p := range pending {
+		if p[0] != key {
+			continue
+		}
+		if len(p) == 1 {
+			matched = true
+		} else {
+			rest = append(rest, p[1:])
+		}
+	}
+	return rest, matched
+}
+
+// hide returns a redacted copy of a sensitive value. Empty values are left alone.
+func hide(rv reflect.Value) (reflect.Value, bool) {
+	if isEmpty(rv) {
+		return rv, false
+	}
+	t := rv.Type()
+	switch rv.Kind() {
+	case reflect.String:
+		out := reflect.New(t).Elem()
+		out.SetString(Redacted)
+		return out, true
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			out := reflect.New(t).Elem()
+			out.SetBytes([]byte(Redacted))
+			return out, true
+		}
+		out := copyList(rv)
+		for i := range rv.Len() {
+			elem, _ := hide(rv.Index(i))
+			out.Index(i).Set(elem)
+		}
+		return out, true
+	case reflect.Map:
+		out := reflect.MakeMapWithSize(t, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			elem, _ := hide(iter.Value())
+			out.SetMapIndex(iter.Key(), elem)
+		}
+		return out, true
+	case reflect.Pointer:
+		elem, _ := hide(rv.Elem())
+		out := reflect.New(t.Elem())
+		out.Elem().Set(elem)
+		return out, true
+	case reflect.Interface:
+		if reflect.TypeFor[string]().Implements(t) {
+			out := reflect.New(t).Elem()
+			out.Set(reflect.ValueOf(Redacted))
+			return out, true
+		}
+	}
+	return reflect.Zero(t), true
+}
+
+// holdsString reports whether rv is a string, possibly behind pointers or interfaces.
+func holdsString(rv reflect.Value) bool {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return false
+		}
+		rv = rv.Elem()
+	}
+	return rv.Kind() == reflect.String
+}
+
+func isEmpty(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return rv.Len() == 0
+	}
+	return rv.IsZero()
+}
+
+// copyList returns a modifiable copy of a slice or array.
+func copyList(rv reflect.Value) reflect.Value {
+	if rv.Kind() == reflect.Array {
+		out := reflect.New(rv.Type()).Elem()
+		out.Set(rv)
+		return out
+	}
+	out := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+	reflect.Copy(out, rv)
+	return out
+}
+
+// orig returns out when it was created and rv otherwise.
+func orig(rv, out reflect.Value) (reflect.Value, bool) {
+	if out.IsValid() {
+		return out, true
+	}
+	return rv, false
+}
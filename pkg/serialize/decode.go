@@ -0,0 +1,305 @@
+package serialize
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kjson "sigs.k8s.io/json"
+)
+
+// Document is one object decoded from a manifest stream.
+type Document struct {
+	// Object is a typed object such as *v1alpha1.Machine, or *unstructured.Unstructured for
+	// kinds the scheme does not know.
+	Object runtime.Object
+	GVK    schema.GroupVersionKind
+	// File and Line locate the start of the document.
+	File string
+	Line int
+}
+
+// DecodeError is a problem with a document, located by file and line.
+type DecodeError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e *DecodeError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// DecodeOptions configures Decode.
+type DecodeOptions struct {
+	// Scheme resolves apiVersion/kind to Go types (default: client-go plus vitistack v1alpha1).
+	Scheme *runtime.Scheme
+	// Strict reports fields that the typed object does not have, each as a *DecodeError.
+	Strict bool
+}
+
+// Decode reads a multi-document YAML stream (documents separated by ---) or a stream of JSON
+// objects and returns one Document per object. Items of a kind: List document are returned
+// individually. Empty documents are skipped.
+func Decode(r io.Reader) ([]Document, error) { return DecodeOptions{}.Decode(r, "") }
+
+// DecodeFile decodes every document in the file at path.
+func DecodeFile(path string) ([]Document, error) { return DecodeOptions{}.DecodeFiles(path) }
+
+// DecodeFiles decodes the documents of every file in order.
+//
+//	files, _ := filepath.Glob("examples/machineclasses/*.yaml")
+//	docs, err := serialize.DecodeOptions{Strict: true}.DecodeFiles(files...)
+func (o DecodeOptions) DecodeFiles(paths ...string) ([]Document, error) {
+	var docs []Document
+	var errs []error
+	for _, p := range paths {
+		f, err := os.Open(p) // #nosec G304 -- manifest paths are chosen by the caller
+		if err != nil {
+			return docs, err
+		}
+		d, err := o.Decode(f, p)
+		_ = f.Close()
+		docs = append(docs, d...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return docs, errors.Join(errs...)
+}
+
+// Decode decodes r; file is only used in error messages and Document.File. Syntax errors stop
+// decoding. In strict mode all documents are decoded and the unknown-field errors are joined.
+func (o DecodeOptions) Decode(r io.Reader, file string) ([]Document, error) {
+	if o.Scheme == nil {
+		o.Scheme = defaultScheme()
+	}
+	nodes, err := readNodes(r)
+	if err != nil {
+		return nil, &DecodeError{File: file, Line: lineOf(err), Err: err}
+	}
+
+	var docs []Document
+	var errs []error
+	for _, n := range nodes {
+		d, derrs, err := o.decodeNode(n, file)
+		if err != nil {
+			return docs, err
+		}
+		docs = append(docs, d...)
+		errs = append(errs, derrs...)
+	}
+	return docs, errors.Join(errs...)
+}
+
+// readNodes parses every document in the stream, keeping line numbers. JSON streams are read
+// object by object since they have no --- separators.
+func readNodes(r io.Reader) ([]*yaml.Node, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return readJSONNodes(data)
+	}
+	var nodes []*yaml.Node
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		n := &yaml.Node{}
+		if err := dec.Decode(n); errors.Is(err, io.EOF) {
+			return nodes, nil
+		} else if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+}
+
+func readJSONNodes(data []byte) ([]*yaml.Node, error) {
+	var nodes []*yaml.Node
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var raw json.RawMessage
+		offset := dec.InputOffset()
+		if err := dec.Decode(&raw); errors.Is(err, io.EOF) {
+			return nodes, nil
+		} else if err != nil {
+			return nil, err
+		}
+		// Line numbers of the document are relative to where it starts in the stream.
+		start := offset + int64(len(data[offset:])-len(bytes.TrimLeft(data[offset:], " \t\r\n")))
+		n := &yaml.Node{}
+		if err := yaml.Unmarshal(raw, n); err != nil {
+			return nil, err
+		}
+		shiftLines(n, bytes.Count(data[:start], []byte("\n")))
+		nodes = append(nodes, n)
+	}
+}
+
+func shiftLines(n *yaml.Node, by int) {
+	n.Line += by
+	for _, c := range n.Content {
+		shiftLines(c, by)
+	}
+}
+
+func (o DecodeOptions) decodeNode(n *yaml.Node, file string) ([]Document, []error, error) {
+	root := n
+	if root.Kind == yaml.DocumentNode {
+		if len(root.Content) == 0 {
+			return nil, nil, nil
+		}
+		root = root.Content[0]
+	}
+	if root.Kind == yaml.ScalarNode && root.Tag == "!!null" {
+		return nil, nil, nil
+	}
+	fail := func(err error) *DecodeError { return &DecodeError{File: file, Line: root.Line, Err: err} }
+
+	var v any
+	if err := root.Decode(&v); err != nil {
+		return nil, nil, fail(err)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, nil, fail(err)
+	}
+	var obj map[string]any
+	if err := kjson.UnmarshalCaseSensitivePreserveInts(data, &obj); err != nil {
+		return nil, nil, fail(errors.New("document is not an object"))
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	gvk := u.GroupVersionKind()
+	if gvk.Kind == "" || u.GetAPIVersion() == "" {
+		return nil, nil, fail(errors.New("document has no apiVersion or kind"))
+	}
+
+	if gvk.Kind == "List" {
+		return o.decodeList(root, file)
+	}
+	doc := Document{Object: u, GVK: gvk, File: file, Line: root.Line}
+	if !o.Scheme.Recognizes(gvk) {
+		return []Document{doc}, nil, nil
+	}
+	typed, err := o.Scheme.New(gvk)
+	if err != nil {
+		return nil, nil, fail(err)
+	}
+	strictErrs, err := kjson.UnmarshalStrict(data, typed, kjson.DisallowUnknownFields)
+	if err != nil {
+		return nil, nil, fail(err)
+	}
+	typed.GetObjectKind().SetGroupVersionKind(gvk)
+	doc.Object = typed
+
+	var errs []error
+	if o.Strict {
+		for _, se := range strictErrs {
+			line := root.Line
+			if fp, ok := se.(interface{ FieldPath() string }); ok {
+				line = fieldLine(root, fp.FieldPath())
+			}
+			errs = append(errs, &DecodeError{File: file, Line: line, Err: fmt.Errorf("%s: %w", gvk.Kind, se)})
+		}
+	}
+	return []Document{doc}, errs, nil
+}
+
+// decodeList decodes the items of a v1 List one by one so each keeps its own line number.
+func (o DecodeOptions) decodeList(root *yaml.Node, file string) ([]Document, []error, error) {
+	items := mappingValue(root, "items")
+	if items == nil || items.Kind != yaml.SequenceNode {
+		return nil, nil, nil
+	}
+	var docs []Document
+	var errs []error
+	for _, item := range items.Content {
+		d, derrs, err := o.decodeNode(item, file)
+		if err != nil {
+			return docs, errs, err
+		}
+		docs = append(docs, d...)
+		errs = append(errs, derrs...)
+	}
+	return docs, errs, nil
+}
+
+// fieldLine finds the line of a strict-error field path such as spec.cpu.core or
+// spec.disks[0].sise. Map keys containing dots are matched greedily.
+func fieldLine(n *yaml.Node, path string) int {
+	line := n.Line
+	for path != "" {
+		switch n.Kind {
+		case yaml.MappingNode:
+			best := -1
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				k := n.Content[i].Value
+				if (path == k || strings.HasPrefix(path, k+".") || strings.HasPrefix(path, k+"[")) &&
+					(best < 0 || len(k) > len(n.Content[best].Value)) {
+					best = i
+				}
+			}
+			if best < 0 {
+				return line
+			}
+			line = n.Content[best].Line
+			path = strings.TrimPrefix(strings.TrimPrefix(path, n.Content[best].Value), ".")
+			n = n.Content[best+1]
+		case yaml.SequenceNode:
+			end := strings.IndexByte(path, ']')
+			if !strings.HasPrefix(path, "[") || end < 0 {
+				return line
+			}
+			i, err := strconv.Atoi(path[1:end])
+			if err != nil || i < 0 || i >= len(n.Content) {
+				return line
+			}
+			n = n.Content[i]
+			line = n.Line
+			path = strings.TrimPrefix(path[end+1:], ".")
+		default:
+			return line
+		}
+	}
+	return line
+}
+
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// lineOf extracts the line number from a yaml.v3 syntax error ("yaml: line 3: ...").
+func lineOf(err error) int {
+	msg := err.Error()
+	if _, rest, ok := strings.Cut(msg, "line "); ok {
+		if end := strings.IndexByte(rest, ':'); end > 0 {
+			if n, convErr := strconv.Atoi(rest[:end]); convErr == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
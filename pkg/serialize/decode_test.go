@@ -0,0 +1,145 @@
+package serialize
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vitistack/common/pkg/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDecode(t *testing.T) {
+	input := `---
+apiVersion: vitistack.io/v1alpha1
+kind: Machine
+metadata:
+  name: m1
+spec:
+  machineClass: small
+---
+# empty documents are skipped
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: w1
+spec:
+  size: 3
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: vitistack.io/v1alpha1
+  kind: MachineClass
+  metadata:
+    name: small
+`
+	docs, err := Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Decode() unexpected error: %v", err)
+	}
+	if len(docs) != 3 {
+		t.Fatalf("Decode() = %d documents, want 3", len(docs))
+	}
+
+	m, ok := docs[0].Object.(*v1alpha1.Machine)
+	if !ok || m.Name != "m1" || m.Spec.MachineClass != "small" || m.Kind != "Machine" || docs[0].Line != 2 {
+		t.Errorf("docs[0] = %+v (line %d), want typed Machine m1 at line 2", docs[0].Object, docs[0].Line)
+	}
+	u, ok := docs[1].Object.(*unstructured.Unstructured)
+	if !ok || u.GetName() != "w1" || docs[1].GVK.Kind != "Widget" {
+		t.Errorf("docs[1] = %T, want unstructured Widget", docs[1].Object)
+	} else if size, _, _ := unstructured.NestedInt64(u.Object, "spec", "size"); size != 3 {
+		t.Errorf("spec.size = %d, want int64 3", size)
+	}
+	if mc, ok := docs[2].Object.(*v1alpha1.MachineClass); !ok || mc.Name != "small" || docs[2].Line != 21 {
+		t.Errorf("docs[2] = %T (line %d), want MachineClass from List at line 21", docs[2].Object, docs[2].Line)
+	}
+}
+
+func TestDecode_Strict(t *testing.T) {
+	input := `apiVersion: vitistack.io/v1alpha1
+kind: Machine
+metadata:
+  name: m1
+spec:
+  machineClass: small
+  machineKlass: typo
+  disks:
+  - name: root
+    sise: 10Gi
+`
+	if _, err := Decode(strings.NewReader(input)); err != nil {
+		t.Fatalf("non-strict Decode() unexpected error: %v", err)
+	}
+
+	docs, err := DecodeOptions{Strict: true}.Decode(strings.NewReader(input), "machine.yaml")
+	if len(docs) != 1 {
+		t.Errorf("strict Decode() should still return the document, got %d", len(docs))
+	}
+	var de *DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("strict Decode() error = %v, want *DecodeError", err)
+	}
+	msg := err.Error()
+	for _, want := range []string{`machine.yaml:7: Machine: unknown field "spec.machineKlass"`, `machine.yaml:10: Machine: unknown field "spec.disks[0].sise"`} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error %q should contain %q", msg, want)
+		}
+	}
+}
+
+func TestDecode_JSONStreamAndErrors(t *testing.T) {
+	input := `{"apiVersion": "vitistack.io/v1alpha1", "kind": "Machine", "metadata": {"name": "a"}}
+{
+  "apiVersion": "vitistack.io/v1alpha1",
+  "kind": "Machine",
+  "metadata": {"name": "b"},
+  "spec": {"bogus": true}
+}`
+	docs, err := DecodeOptions{Strict: true}.Decode(strings.NewReader(input), "stream.json")
+	if len(docs) != 2 || docs[1].Line != 2 {
+		t.Fatalf("Decode() = %+v, want 2 documents with the second at line 2", docs)
+	}
+	if err == nil || !strings.Contains(err.Error(), "stream.json:6:") {
+		t.Errorf("error = %v, want unknown field at line 6", err)
+	}
+
+	_, err = Decode(strings.NewReader("kind: Machine\nmetadata: {}\n"))
+	if err == nil || !strings.Contains(err.Error(), "no apiVersion or kind") {
+		t.Errorf("error = %v, want missing apiVersion", err)
+	}
+	_, err = Decode(strings.NewReader("apiVersion: v1\nkind: [unterminated\n"))
+	var de *DecodeError
+	if !errors.As(err, &de) || de.Line == 0 {
+		t.Errorf("error = %v, want syntax error with a line number", err)
+	}
+}
+
+func TestDecodeFiles_RepositoryManifests(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "..", "examples", "machineclasses", "*.yaml"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no example machine classes found: %v", err)
+	}
+	docs, err := DecodeOptions{Strict: true}.DecodeFiles(files...)
+	if err != nil {
+		t.Fatalf("DecodeFiles() unexpected error: %v", err)
+	}
+	for _, d := range docs {
+		if _, ok := d.Object.(*v1alpha1.MachineClass); !ok {
+			t.Errorf("%s:%d decoded as %T, want *v1alpha1.MachineClass", d.File, d.Line, d.Object)
+		}
+	}
+
+	crds, err := DecodeFile(filepath.Join("..", "..", "crds.yaml"))
+	if err != nil {
+		t.Fatalf("DecodeFile(crds.yaml) unexpected error: %v", err)
+	}
+	for _, d := range crds {
+		if d.GVK.Kind != "CustomResourceDefinition" {
+			t.Errorf("crds.yaml:%d kind = %s", d.Line, d.GVK.Kind)
+		}
+	}
+}
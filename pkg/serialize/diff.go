@@ -0,0 +1,289 @@
+package serialize
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vitistack/common/pkg/redact"
+)
+
+// Change operations reported by Diff.
+const (
+	OpAdd    = "add"
+	OpRemove = "remove"
+	OpChange = "change"
+)
+
+// Change is a single difference between two objects. Old is nil for OpAdd and New is nil for
+// OpRemove. Sensitive values are reported as Redacted unless redaction is disabled.
+type Change struct {
+	// Path uses the JSON field names, e.g. spec.topology.workers.nodePools[name=pool-a].replicas.
+	// Items of keyed lists are addressed by key, other list items by index.
+	Path string `json:"path"`
+	Op   string `json:"op"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+func (c Change) String() string {
+	switch c.Op {
+	case OpAdd:
+		return fmt.Sprintf("%s: added %s", c.Path, JSON(c.New))
+	case OpRemove:
+		return fmt.Sprintf("%s: removed %s", c.Path, JSON(c.Old))
+	default:
+		return fmt.Sprintf("%s: %s -> %s", c.Path, JSON(c.Old), JSON(c.New))
+	}
+}
+
+// Changes is the result of Diff, sorted by path.
+type Changes []Change
+
+// String returns one line per change, suitable for logs and CLI output.
+func (c Changes) String() string {
+	lines := make([]string, 0, len(c))
+	for _, ch := range c {
+		lines = append(lines, ch.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Summary returns the changed paths on one line, e.g. for an event message.
+func (c Changes) Summary() string {
+	if len(c) == 0 {
+		return "no changes"
+	}
+	paths := make([]string, 0, len(c))
+	for _, ch := range c {
+		paths = append(paths, ch.Path)
+	}
+	return "changed " + strings.Join(paths, ", ")
+}
+
+// DefaultIgnorePaths is metadata that changes on every write and is skipped by Diff.
+var DefaultIgnorePaths = []string{
+	"metadata.resourceVersion",
+	"metadata.managedFields",
+	"metadata.generation",
+	"metadata.uid",
+	"metadata.creationTimestamp",
+	"metadata.selfLink",
+	`metadata.annotations["kubectl.kubernetes.io/last-applied-configuration"]`,
+}
+
+// DefaultListKeys are the fields, tried in order, that identify list items. A comma separated
+// entry is a composite key. Lists whose items are not uniquely identified are compared by index.
+var DefaultListKeys = []string{"name", "key", "key,effect"}
+
+// DiffOptions configures Diff.
+type DiffOptions struct {
+	// IgnorePaths are skipped together with everything below them (default DefaultIgnorePaths).
+	IgnorePaths []string
+	// ListKeys identify list items (default DefaultListKeys).
+	ListKeys []string
+}
+
+// Diff returns the differences between oldObj and newObj, compared in their JSON form. Lists of
+// objects are matched by name (or another DefaultListKeys field) so a change to one node pool,
+// taint or interface is reported at that item rather than as a shifted list. Metadata noise
+// such as resourceVersion and managedFields is ignored.
+//
+//	changes := serialize.Diff(oldCluster, newCluster)
+//	vlog.Info("cluster spec changed", "changes", changes.String())
+func Diff(oldObj, newObj any) Changes { return DiffOptions{}.Diff(oldObj, newObj) }
+
+// Diff compares oldObj and newObj using these options.
+func (o DiffOptions) Diff(oldObj, newObj any) Changes {
+	if o.IgnorePaths == nil {
+		o.IgnorePaths = DefaultIgnorePaths
+	}
+	if o.ListKeys == nil {
+		o.ListKeys = DefaultListKeys
+	}
+	a, ra := diffTrees(oldObj)
+	b, rb := diffTrees(newObj)
+	d := &differ{opts: o}
+	d.compare("", a, b, ra, rb)
+	sort.SliceStable(d.changes, func(i, j int) bool { return d.changes[i].Path < d.changes[j].Path })
+	return d.changes
+}
+
+// diffTrees returns the JSON form of v and, when redaction is enabled, its redacted form.
+func diffTrees(v any) (tree, redactedTree any) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v), fmt.Sprint(v)
+	}
+	_ = json.Unmarshal(b, &tree)
+	if !RedactionEnabled() {
+		return tree, tree
+	}
+	_ = json.Unmarshal(b, &redactedTree)
+	redact.Tree(v, redactedTree)
+	return tree, redactedTree
+}
+
+type differ struct {
+	opts    DiffOptions
+	changes Changes
+}
+
+// compare walks a and b in parallel with their redacted counterparts ra and rb.
+func (d *differ) compare(path string, a, b, ra, rb any) {
+	if d.ignored(path) || reflect.DeepEqual(a, b) {
+		return
+	}
+	// A redacted subtree is reported as a whole without revealing what changed inside it.
+	if ra == Redacted || rb == Redacted {
+		d.add(path, a, b, ra, rb)
+		return
+	}
+
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		ram, _ := ra.(map[string]any)
+		rbm, _ := rb.(map[string]any)
+		for k, v := range av {
+			d.compare(joinPath(path, k), v, bv[k], ram[k], rbm[k])
+		}
+		for k, v := range bv {
+			if _, seen := av[k]; !seen {
+				d.compare(joinPath(path, k), nil, v, nil, rbm[k])
+			}
+		}
+		return
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+		d.compareLists(path, av, bv, asList(ra), asList(rb))
+		return
+	}
+	d.add(path, a, b, ra, rb)
+}
+
+func (d *differ) compareLists(path string, a, b, ra, rb []any) {
+	if key := d.listKey(a, b); key != "" {
+		bi := map[string]int{}
+		for i, item := range b {
+			bi[itemID(item, key)] = i
+		}
+		seen := map[string]bool{}
+		for i, item := range a {
+			id := itemID(item, key)
+			seen[id] = true
+			p := fmt.Sprintf("%s[%s=%s]", path, key, id)
+			if j, ok := bi[id]; ok {
+				d.compare(p, item, b[j], at(ra, i), at(rb, j))
+			} else {
+				d.compare(p, item, nil, at(ra, i), nil)
+			}
+		}
+		for j, item := range b {
+			if id := itemID(item, key); !seen[id] {
+				d.compare(fmt.Sprintf("%s[%s=%s]", path, key, id), nil, item, nil, at(rb, j))
+			}
+		}
+		return
+	}
+	for i := 0; i < len(a) || i < len(b); i++ {
+		d.compare(fmt.Sprintf("%s[%d]", path, i), at(a, i), at(b, i), at(ra, i), at(rb, i))
+	}
+}
+
+// listKey returns the first configured key that uniquely identifies every item of a and b.
+func (d *differ) listKey(a, b []any) string {
+	if len(a) == 0 && len(b) == 0 {
+		return ""
+	}
+outer:
+	for _, key := range d.opts.ListKeys {
+		for _, list := range [][]any{a, b} {
+			ids := map[string]bool{}
+			for _, item := range list {
+				id := itemID(item, key)
+				if id == "" || ids[id] {
+					continue outer
+				}
+				ids[id] = true
+			}
+		}
+		return key
+	}
+	return ""
+}
+
+// itemID returns the value of key (or the comma separated composite key) in item, or "".
+func itemID(item any, key string) string {
+	m, ok := item.(map[string]any)
+	if !ok {
+		return ""
+	}
+	parts := strings.Split(key, ",")
+	vals := make([]string, 0, len(parts))
+	for _, k := range parts {
+		v, ok := m[k]
+		if !ok || v == nil || v == "" {
+			return ""
+		}
+		vals = append(vals, fmt.Sprint(v))
+	}
+	return strings.Join(vals, ",")
+}
+
+func (d *differ) add(path string, a, b, ra, rb any) {
+	c := Change{Path: path, Old: ra, New: rb}
+	switch {
+	case a == nil:
+		c.Op, c.Old = OpAdd, nil
+	case b == nil:
+		c.Op, c.New = OpRemove, nil
+	default:
+		c.Op = OpChange
+	}
+	d.changes = append(d.changes, c)
+}
+
+func (d *differ) ignored(path string) bool {
+	for _, p := range d.opts.IgnorePaths {
+		if path == p || strings.HasPrefix(path, p+".") || strings.HasPrefix(path, p+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// joinPath appends a map key, quoting keys that are not plain identifiers.
+func joinPath(path, key string) string {
+	if key == "" || strings.ContainsAny(key, ".[]\"/ ") {
+		return path + "[" + strconv.Quote(key) + "]"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func asList(v any) []any {
+	l, _ := v.([]any)
+	return l
+}
+
+func at(l []any, i int) any {
+	if i < len(l) {
+		return l[i]
+	}
+	return nil
+}
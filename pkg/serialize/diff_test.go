@@ -0,0 +1,109 @@
+package serialize
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vitistack/common/pkg/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDiff(t *testing.T) {
+	cluster := func(mutate func(*v1alpha1.KubernetesCluster)) *v1alpha1.KubernetesCluster {
+		kc := &v1alpha1.KubernetesCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "kc", ResourceVersion: "1", Generation: 1},
+		}
+		kc.Spec.Topology.Workers.NodePools = []v1alpha1.KubernetesClusterNodePool{
+			{Name: "pool-a", Replicas: 3, Taint: []v1alpha1.KubernetesClusterTaint{{Key: "dedicated", Value: "db", Effect: "NoSchedule"}}},
+			{Name: "pool-b", Replicas: 1},
+		}
+		if mutate != nil {
+			mutate(kc)
+		}
+		return kc
+	}
+
+	tests := []struct {
+		name string
+		new  *v1alpha1.KubernetesCluster
+		want []string
+	}{
+		{
+			name: "metadata noise only",
+			new: cluster(func(kc *v1alpha1.KubernetesCluster) {
+				kc.ResourceVersion, kc.Generation = "2", 2
+				kc.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "kubectl"}}
+			}),
+		},
+		{
+			name: "keyed by name despite reordering",
+			new: cluster(func(kc *v1alpha1.KubernetesCluster) {
+				pools := kc.Spec.Topology.Workers.NodePools
+				pools[0], pools[1] = pools[1], pools[0]
+				pools[1].Replicas = 5
+			}),
+			want: []string{"spec.topology.workers.nodePools[name=pool-a].replicas: 3 -> 5"},
+		},
+		{
+			name: "taint keyed by key",
+			new: cluster(func(kc *v1alpha1.KubernetesCluster) {
+				kc.Spec.Topology.Workers.NodePools[0].Taint[0].Value = "cache"
+			}),
+			want: []string{`spec.topology.workers.nodePools[name=pool-a].taint[key=dedicated].value: "db" -> "cache"`},
+		},
+		{
+			name: "added and removed pools",
+			new: cluster(func(kc *v1alpha1.KubernetesCluster) {
+				kc.Spec.Topology.Workers.NodePools[1].Name = "pool-c"
+			}),
+			want: []string{
+				"spec.topology.workers.nodePools[name=pool-b]: removed",
+				"spec.topology.workers.nodePools[name=pool-c]: added",
+			},
+		},
+		{
+			name: "labels with dotted keys",
+			new: cluster(func(kc *v1alpha1.KubernetesCluster) {
+				kc.Labels = map[string]string{"app.kubernetes.io/name": "x"}
+			}),
+			want: []string{`metadata.labels: added {"app.kubernetes.io/name":"x"}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := Diff(cluster(nil), tt.new)
+			if len(changes) != len(tt.want) {
+				t.Fatalf("Diff() = %d changes, want %d:\n%s", len(changes), len(tt.want), changes)
+			}
+			for i, want := range tt.want {
+				if got := changes[i].String(); !strings.HasPrefix(got, want) {
+					t.Errorf("change %d = %q, want prefix %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDiff_IndexAndRedaction(t *testing.T) {
+	oldSpec := v1alpha1.ProxmoxConfigSpec{Endpoint: "pve", Token: "old-token"}
+	newSpec := v1alpha1.ProxmoxConfigSpec{Endpoint: "pve", Token: "new-token"}
+	changes := Diff(oldSpec, newSpec)
+	if len(changes) != 1 || changes[0].Path != "token" || changes[0].Old != Redacted || changes[0].New != Redacted {
+		t.Fatalf("Diff() = %+v, want redacted token change", changes)
+	}
+	if strings.Contains(changes.String(), "old-token") || strings.Contains(changes.String(), "new-token") {
+		t.Errorf("secret value leaked: %s", changes)
+	}
+
+	changes = Diff(map[string]any{"args": []any{"a", "b"}}, map[string]any{"args": []any{"a", "c", "d"}})
+	if got := changes.Summary(); got != "changed args[1], args[2]" {
+		t.Errorf("Summary() = %q", got)
+	}
+	if changes[1].Op != OpAdd || changes[1].New != "d" {
+		t.Errorf("change = %+v, want add of d", changes[1])
+	}
+	if Diff(nil, nil).Summary() != "no changes" {
+		t.Errorf("expected no changes")
+	}
+}
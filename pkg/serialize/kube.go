@@ -0,0 +1,156 @@
+package serialize
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/vitistack/common/pkg/redact"
+	"github.com/vitistack/common/pkg/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// KubeOptions controls KubeYAML output.
+type KubeOptions struct {
+	// Scheme resolves apiVersion and kind for typed objects that have no TypeMeta set.
+	// Defaults to the client-go scheme plus vitistack v1alpha1.
+	Scheme *runtime.Scheme
+	// KeepStatus keeps an empty status; a populated status is always kept.
+	KeepStatus bool
+	// KeepManagedFields keeps metadata.managedFields.
+	KeepManagedFields bool
+}
+
+var defaultScheme = sync.OnceValue(func() *runtime.Scheme {
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = v1alpha1.AddToScheme(s)
+	return s
+})
+
+// KubeYAML returns v as Kubernetes manifest YAML: field names follow the json tags, apiVersion
+// and kind are filled in from the scheme, and an empty status, managedFields and a null
+// creationTimestamp are dropped. Sensitive values, such as Secret data, are redacted like JSON,
+// so the output is for logs and messages; use BytesKubeYAML for manifests that are applied.
+// On error, it returns a best-effort fallback using fmt with the error appended.
+func KubeYAML(v any) string {
+	m, err := KubeOptions{}.toMap(v)
+	if err != nil {
+		return fallback(v, err)
+	}
+	if RedactionEnabled() {
+		redact.Tree(v, m)
+	}
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return fallback(v, err)
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// BytesKubeYAML returns v as manifest YAML like KubeYAML, and any error encountered. Like the
+// other Bytes helpers it does not redact: the output is complete and can be passed to kubectl
+// apply, so do not log it.
+func BytesKubeYAML(v any) ([]byte, error) { return KubeOptions{}.YAML(v) }
+
+// YAML renders v like BytesKubeYAML using these options. It does not redact.
+func (o KubeOptions) YAML(v any) ([]byte, error) {
+	m, err := o.toMap(v)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(m)
+}
+
+// toMap converts v to its JSON form and applies the manifest clean-ups.
+func (o KubeOptions) toMap(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	obj, ok := m.(map[string]any)
+	if !ok {
+		return m, nil
+	}
+
+	if ro, isObject := v.(runtime.Object); isObject {
+		o.setTypeMeta(ro, obj)
+	}
+	o.clean(obj)
+	if items, isList := obj["items"].([]any); isList {
+		itemKind := strings.TrimSuffix(stringField(obj, "kind"), "List")
+		for _, item := range items {
+			if im, isMap := item.(map[string]any); isMap {
+				if stringField(im, "kind") == "" && itemKind != "" && itemKind != stringField(obj, "kind") {
+					im["apiVersion"], im["kind"] = obj["apiVersion"], itemKind
+				}
+				o.clean(im)
+			}
+		}
+	}
+	return obj, nil
+}
+
+func (o KubeOptions) setTypeMeta(ro runtime.Object, obj map[string]any) {
+	if stringField(obj, "kind") != "" {
+		return
+	}
+	scheme := o.Scheme
+	if scheme == nil {
+		scheme = defaultScheme()
+	}
+	gvks, _, err := scheme.ObjectKinds(ro)
+	if err != nil || len(gvks) == 0 {
+		return
+	}
+	obj["apiVersion"], obj["kind"] = gvks[0].GroupVersion().String(), gvks[0].Kind
+}
+
+func (o KubeOptions) clean(obj map[string]any) {
+	if meta, ok := obj["metadata"].(map[string]any); ok {
+		if !o.KeepManagedFields {
+			delete(meta, "managedFields")
+		}
+		if meta["creationTimestamp"] == nil {
+			delete(meta, "creationTimestamp")
+		}
+	}
+	if status, ok := obj["status"]; ok && !o.KeepStatus && isEmpty(status) {
+		delete(obj, "status")
+	}
+}
+
+// isEmpty reports whether v holds only zero values, e.g. {"phase": "", "conditions": null}.
+func isEmpty(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case bool:
+		return !t
+	case float64:
+		return t == 0
+	case []any:
+		return len(t) == 0
+	case map[string]any:
+		for _, item := range t {
+			if !isEmpty(item) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func stringField(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
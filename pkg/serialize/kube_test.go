@@ -0,0 +1,118 @@
+package serialize
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vitistack/common/pkg/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestKubeYAML(t *testing.T) {
+	machine := &v1alpha1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "m1",
+			Namespace:     "default",
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+		},
+		Spec: v1alpha1.MachineSpec{Name: "m1", MachineClass: "small"},
+	}
+
+	tests := []struct {
+		name     string
+		input    any
+		opts     KubeOptions
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "typed object",
+			input:    machine,
+			contains: []string{"apiVersion: vitistack.io/v1alpha1", "kind: Machine", "machineClass: small", "name: m1"},
+			excludes: []string{"objectmeta", "machineclass", "managedFields", "status:", "creationTimestamp"},
+		},
+		{
+			name:     "keep status and managed fields",
+			input:    machine,
+			opts:     KubeOptions{KeepStatus: true, KeepManagedFields: true},
+			contains: []string{"status:", "managedFields:", "manager: kubectl"},
+		},
+		{
+			name:     "populated status is kept",
+			input:    &v1alpha1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "m2"}, Status: v1alpha1.MachineStatus{Phase: v1alpha1.MachinePhaseRunning}},
+			contains: []string{"status:", "phase: Running"},
+		},
+		{
+			name:     "core type",
+			input:    &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}, Data: map[string]string{"k": "v"}},
+			contains: []string{"apiVersion: v1", "kind: ConfigMap"},
+		},
+		{
+			name:     "list items get kind",
+			input:    &v1alpha1.MachineList{Items: []v1alpha1.Machine{*machine}},
+			contains: []string{"kind: MachineList", "- apiVersion: vitistack.io/v1alpha1", "kind: Machine\n"},
+			excludes: []string{"managedFields"},
+		},
+		{
+			name:     "plain struct",
+			input:    struct{ Name string }{testString},
+			contains: []string{"Name: test"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.opts.YAML(tt.input)
+			if err != nil {
+				t.Fatalf("YAML() unexpected error: %v", err)
+			}
+			result := string(b)
+			for _, s := range tt.contains {
+				if !strings.Contains(result, s) {
+					t.Errorf("output should contain %q:\n%s", s, result)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(result, s) {
+					t.Errorf("output should not contain %q:\n%s", s, result)
+				}
+			}
+		})
+	}
+}
+
+func TestKubeYAML_RedactsOnlyForPrinting(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3", Namespace: "default"},
+		StringData: map[string]string{"accessKey": "AK"},
+	}
+	if out := KubeYAML(secret); strings.Contains(out, "AK") || !strings.Contains(out, Redacted) {
+		t.Errorf("KubeYAML() must redact Secret data:\n%s", out)
+	}
+	b, err := BytesKubeYAML(secret)
+	if err != nil {
+		t.Fatalf("BytesKubeYAML() unexpected error: %v", err)
+	}
+	if !strings.Contains(string(b), "accessKey: AK") || !strings.Contains(string(b), "kind: Secret") {
+		t.Errorf("BytesKubeYAML() must return the complete manifest:\n%s", b)
+	}
+}
+
+func TestKubeYAML_RoundTrip(t *testing.T) {
+	in := &v1alpha1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "m1", Labels: map[string]string{"app": "x"}},
+		Spec:       v1alpha1.MachineSpec{Name: "m1", MachineClass: "small"},
+	}
+	var out v1alpha1.Machine
+	if err := yaml.Unmarshal([]byte(KubeYAML(in)), &out); err != nil {
+		t.Fatalf("Unmarshal() unexpected error: %v", err)
+	}
+	if out.Kind != "Machine" || out.Name != "m1" || out.Labels["app"] != "x" || out.Spec.MachineClass != "small" {
+		t.Errorf("round trip mismatch: %+v", out)
+	}
+	if in.Kind != "" {
+		t.Errorf("KubeYAML must not modify its input, kind = %q", in.Kind)
+	}
+}
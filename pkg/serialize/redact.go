@@ -0,0 +1,35 @@
+package serialize
+
+import (
+	"github.com/vitistack/common/pkg/redact"
+)
+
+// Redacted replaces sensitive values in serialized output.
+const Redacted = redact.Redacted
+
+// SetRedaction turns redaction in the string helpers (JSON, Pretty, YAML, KubeYAML, ...) on or
+// off. It is on by default. The Bytes* helpers never redact. See redact.SetEnabled.
+func SetRedaction(enabled bool) { redact.SetEnabled(enabled) }
+
+// RedactionEnabled reports whether the string helpers redact sensitive values.
+func RedactionEnabled() bool { return redact.Enabled() }
+
+// RegisterSensitive marks JSON paths (dot separated, relative to the type of sample) as
+// sensitive wherever a value of that type is serialized; see redact.Register.
+func RegisterSensitive(sample any, paths ...string) { redact.Register(sample, paths...) }
+
+// IsSensitiveKey reports whether a field or variable name looks like it holds a secret; see
+// redact.IsSensitiveKey.
+func IsSensitiveKey(key string) bool { return redact.IsSensitiveKey(key) }
+
+// Redact returns a copy of v with sensitive values replaced by Redacted, or v itself when
+// nothing is sensitive; see redact.Value.
+func Redact(v any) any { return redact.Value(v) }
+
+// redacted applies Redact when redaction is enabled.
+func redacted(v any) any {
+	if !redact.Enabled() {
+		return v
+	}
+	return redact.Value(v)
+}
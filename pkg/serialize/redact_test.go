@@ -0,0 +1,133 @@
+package serialize
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vitistack/common/pkg/clients/s3client/s3interface"
+	"github.com/vitistack/common/pkg/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type redactTestConfig struct {
+	Endpoint  string `json:"endpoint"`
+	APIKey    string `json:"key" redact:"true"`
+	Password  string `json:"password"`
+	SecretKey string `json:"userDataSecretKey" redact:"false"`
+	Nested    []struct {
+		Token string
+	} `json:"nested"`
+}
+
+type registeredTestType struct {
+	Payload map[string]string `json:"payload"`
+}
+
+func TestRedact(t *testing.T) {
+	RegisterSensitive(registeredTestType{}, "payload")
+
+	cfg := redactTestConfig{Endpoint: "https://s3", APIKey: "k", Password: "p", SecretKey: "userdata"}
+	cfg.Nested = append(cfg.Nested, struct{ Token string }{"t"})
+
+	tests := []struct {
+		name     string
+		input    any
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "tags, heuristics and opt-out",
+			input:    &cfg,
+			contains: []string{`"endpoint":"https://s3"`, `"key":"[REDACTED]"`, `"password":"[REDACTED]"`, `"userDataSecretKey":"userdata"`, `"Token":"[REDACTED]"`},
+		},
+		{
+			name:     "proxmox token",
+			input:    v1alpha1.ProxmoxConfigSpec{Endpoint: "pve", Token: "abc"},
+			contains: []string{`"token":"[REDACTED]"`},
+			excludes: []string{"abc"},
+		},
+		{
+			name:     "secret data",
+			input:    &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "s"}, Data: map[string][]byte{"cert": []byte("pem")}},
+			contains: []string{`"data":{"cert":"W1JFREFDVEVEXQ=="}`, `"name":"s"`},
+			excludes: []string{"cGVt"},
+		},
+		{
+			name:     "registered path",
+			input:    registeredTestType{Payload: map[string]string{"a": "b"}},
+			contains: []string{`"payload":{"a":"[REDACTED]"}`},
+		},
+		{
+			name:     "generic map",
+			input:    map[string]any{"user": "u", "credentials": map[string]any{"accessKey": "AK"}},
+			contains: []string{`"user":"u"`, `"accessKey":"[REDACTED]"`},
+		},
+		{
+			name:     "nothing sensitive keeps field order",
+			input:    struct{ B, A string }{"b", "a"},
+			contains: []string{`{"B":"b","A":"a"}`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := JSON(tt.input)
+			for _, s := range tt.contains {
+				if !strings.Contains(result, s) {
+					t.Errorf("JSON() = %s, should contain %s", result, s)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(result, s) {
+					t.Errorf("JSON() = %s, should not contain %s", result, s)
+				}
+			}
+		})
+	}
+
+	if cfg.Password != "p" {
+		t.Errorf("Redact must not modify its input")
+	}
+}
+
+func TestRedact_KeepsShape(t *testing.T) {
+	in := s3interface.Options{Endpoint: "s3", SecretKey: "s3cr3t", BucketName: "b"}
+
+	tests := []struct {
+		name     string
+		format   func(any) string
+		raw      func(any) ([]byte, error)
+		redacted string
+	}{
+		{name: "JSON", format: JSON, raw: BytesJSON, redacted: Redacted},
+		{name: "YAML", format: YAML, raw: BytesYAML, redacted: "'" + Redacted + "'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := tt.raw(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := strings.TrimSpace(strings.Replace(string(raw), "s3cr3t", tt.redacted, 1))
+			if got := tt.format(in); got != want {
+				t.Errorf("%s() = %s, want %s", tt.name, got, want)
+			}
+		})
+	}
+}
+
+func TestRedaction_DisabledAndBytes(t *testing.T) {
+	in := map[string]string{"token": "abc"}
+	if b, _ := BytesJSON(in); !strings.Contains(string(b), "abc") {
+		t.Errorf("BytesJSON must not redact: %s", b)
+	}
+	if !strings.Contains(KubeYAML(&corev1.Secret{StringData: map[string]string{"k": "v"}}), Redacted) {
+		t.Errorf("KubeYAML should redact secret data")
+	}
+
+	SetRedaction(false)
+	defer SetRedaction(true)
+	if got := JSON(in); got != `{"token":"abc"}` {
+		t.Errorf("JSON() with redaction disabled = %s", got)
+	}
+}
@@ -9,9 +9,10 @@ import (
 )
 
 // JSON returns a compact JSON string representation of v.
+// Sensitive values are redacted unless disabled with SetRedaction; see Redact.
 // On error, it returns a best-effort fallback using fmt with the error appended.
 func JSON(v any) string {
-	b, err := json.Marshal(v)
+	b, err := json.Marshal(redacted(v))
 	if err != nil {
 		return fallback(v, err)
 	}
@@ -21,7 +22,7 @@ func JSON(v any) string {
 // JSONIndent pretty-prints v as JSON with the provided indent string (e.g., "  " or "\t").
 // On error, it returns a best-effort fallback using fmt with the error appended.
 func JSONIndent(v any, indent string) string {
-	b, err := json.MarshalIndent(v, "", indent)
+	b, err := json.MarshalIndent(redacted(v), "", indent)
 	if err != nil {
 		return fallback(v, err)
 	}
@@ -43,7 +44,7 @@ func Pretty(v any) string { return JSONIndentN(v, 2) }
 // YAML is naturally indented/pretty-printed by default.
 // On error, it returns a best-effort fallback using fmt with the error appended.
 func YAML(v any) string {
-	b, err := yaml.Marshal(v)
+	b, err := yaml.Marshal(redacted(v))
 	if err != nil {
 		return fallback(v, err)
 	}
@@ -53,7 +54,8 @@ func YAML(v any) string {
 // PrettyYAML is an alias for YAML (YAML is pretty by default).
 func PrettyYAML(v any) string { return YAML(v) }
 
-// BytesYAML returns the YAML bytes and any error encountered.
+// BytesYAML returns the YAML bytes and any error encountered. Like the other Bytes helpers it
+// does not redact.
 func BytesYAML(v any) ([]byte, error) { return yaml.Marshal(v) }
 
 // BytesJSON returns the compact JSON bytes and any error encountered.
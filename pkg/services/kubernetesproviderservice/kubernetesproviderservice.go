@@ -41,7 +41,7 @@ func ListAllKubernetesProviders(ctx context.Context) ([]*vitistackv1alpha1.Kuber
 	for i := range unstructuredList.Items {
 		provider, err := unstructuredutil.KubernetesProviderFromUnstructured(&unstructuredList.Items[i])
 		if err != nil {
-			vlog.Warn("Failed to convert KubernetesProvider, skipping",
+			vlog.FromContext(ctx).Warn("Failed to convert KubernetesProvider, skipping",
 				"name", unstructuredList.Items[i].GetName(),
 				"error", err)
 			continue
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/vitistack/common/pkg/operator/phase"
 	vitistackv1alpha1 "github.com/vitistack/common/pkg/v1alpha1"
 )
 
@@ -157,3 +158,32 @@ func validateNodePools(cluster *vitistackv1alpha1.KubernetesCluster) *Validation
 
 	return nil
 }
+
+// Phase state machines shared with the controllers; only their transition tables are used here
+var (
+	machinePhases           = phase.NewMachineStateMachine()
+	kubernetesClusterPhases = phase.NewKubernetesClusterStateMachine()
+	clusterStoragePhases    = phase.NewClusterStorageStateMachine()
+)
+
+// ValidateMachinePhaseTransition validates that a Machine may move from its old to its new phase
+func (s *ValidationService) ValidateMachinePhaseTransition(oldMachine, newMachine *vitistackv1alpha1.Machine) error {
+	return validatePhaseTransition(machinePhases, oldMachine.Status.Phase, newMachine.Status.Phase)
+}
+
+// ValidateKubernetesClusterPhaseTransition validates that a KubernetesCluster may move from its old to its new phase
+func (s *ValidationService) ValidateKubernetesClusterPhaseTransition(oldCluster, newCluster *vitistackv1alpha1.KubernetesCluster) error {
+	return validatePhaseTransition(kubernetesClusterPhases, oldCluster.Status.Phase, newCluster.Status.Phase)
+}
+
+// ValidateClusterStoragePhaseTransition validates that a ClusterStorage may move from its old to its new phase
+func (s *ValidationService) ValidateClusterStoragePhaseTransition(oldStorage, newStorage *vitistackv1alpha1.ClusterStorage) error {
+	return validatePhaseTransition(clusterStoragePhases, oldStorage.Status.Phase, newStorage.Status.Phase)
+}
+
+func validatePhaseTransition(sm *phase.StateMachine, from, to string) error {
+	if err := sm.Validate(from, to); err != nil {
+		return ValidationErrors{{Field: "status.phase", Message: err.Error()}}
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vitistack/common/pkg/operator/env"
+	"github.com/vitistack/common/pkg/redact"
+	"github.com/vitistack/common/pkg/settings/dotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Sources in increasing order of precedence.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceDotEnv  = "dotenv"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// Redacted replaces secret values in printed configuration.
+const Redacted = redact.Redacted
+
+// ErrUnknownKeys is returned by Load when the config file has keys that match no field.
+var ErrUnknownKeys = errors.New("unknown configuration keys")
+
+// Options configures Load.
+type Options struct {
+	// ConfigFile is a YAML file with configuration keys; it can be overridden with --config.
+	// Keys may be written as env keys (S3_ENDPOINT), flag names (s3-endpoint) or nested maps
+	// (s3: {endpoint: ...}).
+	ConfigFile string
+	// SkipDotEnv disables reading .env files.
+	SkipDotEnv bool
+	// DotEnv selects the .env files; see dotenv.Options. Values are not written to the process env.
+	DotEnv dotenv.Options
+	// FlagSet receives one flag per field plus --config and --print-config. Defaults to a new
+	// FlagSet named after the program. Pass your own to add application-specific flags.
+	FlagSet *flag.FlagSet
+	// Args are the command line arguments (default os.Args[1:]).
+	Args []string
+	// Lookup reads the process environment (default os.LookupEnv).
+	Lookup env.Lookup
+}
+
+// Value is one resolved configuration key and where it came from.
+type Value struct {
+	Key   string
+	Value string
+	// Source is one of the Source* constants.
+	Source string
+	// Origin is the file or flag name the value came from, if any.
+	Origin string
+	// Secret is set for secret fields (see env.Field) and for keys read from a FOO_FILE secret
+	// file; Values and Print redact them.
+	Secret bool
+}
+
+// Result describes the merged configuration.
+type Result struct {
+	// PrintRequested is true when --print-config was passed.
+	PrintRequested bool
+	values         map[string]Value
+	order          []string
+}
+
+// Load fills cfg (a pointer to a struct tagged for env.Load) from, in increasing precedence:
+// built-in defaults, a YAML config file, .env/.env-<ENV>, the process environment and command-line
+// flags. Flags are derived from env keys: S3_ENDPOINT becomes --s3-endpoint.
+//
+// Problems that leave the other sources usable, such as unknown config file keys (ErrUnknownKeys),
+// a .env file that does not parse or invalid values, do not stop Load: cfg is filled from
+// everything else and all problems are returned together with the result. The result is nil
+// only when the arguments, the config file or the .env schema cannot be read.
+//
+// Example:
+//
+//	var cfg Config
+//	res, err := config.Load(&cfg, config.Options{ConfigFile: "/etc/operator/config.yaml"})
+//	if err != nil {
+//		vlog.Fatal(err)
+//	}
+//	if res.PrintRequested {
+//		res.Print(os.Stdout)
+//		os.Exit(0)
+//	}
+func Load(cfg any, opts Options) (*Result, error) {
+	fields := env.Fields(cfg)
+	if fields == nil {
+		return nil, fmt.Errorf("config.Load: expected a pointer to a struct, got %T", cfg)
+	}
+	if opts.Lookup == nil {
+		opts.Lookup = os.LookupEnv
+	}
+	if opts.Args == nil && len(os.Args) > 1 {
+		opts.Args = os.Args[1:]
+	}
+	fs := opts.FlagSet
+	if fs == nil {
+		fs = flag.NewFlagSet(programName(), flag.ContinueOnError)
+	}
+
+	res := &Result{values: map[string]Value{}}
+	known := map[string]*env.Field{}
+	for i := range fields {
+		f := &fields[i]
+		known[f.Key] = f
+		res.order = append(res.order, f.Key)
+	}
+
+	// Flags are parsed first so --config is known, but applied last.
+	configFile := fs.String("config", opts.ConfigFile, "path to a YAML configuration file")
+	printConfig := fs.Bool("print-config", false, "print the resolved configuration and where each value came from")
+	flagValues := map[string]*flagValue{}
+	for i := range fields {
+		f := &fields[i]
+		fv := &flagValue{isBool: f.Type.Kind() == reflect.Bool}
+		flagValues[f.Key] = fv
+		fs.Var(fv, FlagName(f.Key), fmt.Sprintf("overrides %s", f.Key))
+	}
+	if err := fs.Parse(opts.Args); err != nil {
+		return nil, err
+	}
+	res.PrintRequested = *printConfig
+
+	for i := range fields {
+		if fields[i].Default != "" {
+			res.set(&fields[i], fields[i].Default, SourceDefault, "")
+		}
+	}
+	var errs []error
+	if *configFile != "" {
+		kv, err := readYAML(*configFile)
+		if err != nil {
+			return nil, err
+		}
+		var unknown []string
+		for k, v := range kv {
+			if f, ok := known[k]; ok {
+				res.set(f, v, SourceFile, *configFile)
+			} else {
+				unknown = append(unknown, k)
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			errs = append(errs, fmt.Errorf("%s: %w: %s", *configFile, ErrUnknownKeys, strings.Join(unknown, ", ")))
+		}
+	}
+	if !opts.SkipDotEnv {
+		report, err := dotenv.Read(opts.DotEnv)
+		if report == nil {
+			return nil, err
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for k, e := range report.Entries {
+			if f, ok := known[k]; ok {
+				f.Secret = f.Secret || e.Secret
+				res.set(f, e.Value, SourceDotEnv, e.Source)
+			}
+		}
+	}
+	for i := range fields {
+		if v, ok := opts.Lookup(fields[i].Key); ok {
+			res.set(&fields[i], v, SourceEnv, fields[i].Key)
+		}
+	}
+	for key, fv := range flagValues {
+		if fv.set {
+			res.set(known[key], fv.value, SourceFlag, "--"+FlagName(key))
+		}
+	}
+
+	if err := env.LoadWith(cfg, func(key string) (string, bool) {
+		v, ok := res.values[key]
+		return v.Value, ok
+	}); err != nil {
+		errs = append(errs, err)
+	}
+	return res, errors.Join(errs...)
+}
+
+func (r *Result) set(f *env.Field, value, source, origin string) {
+	r.values[f.Key] = Value{Key: f.Key, Value: value, Source: source, Origin: origin, Secret: f.Secret || IsSecretKey(f.Key)}
+}
+
+// Get returns the resolved value for key.
+func (r *Result) Get(key string) (Value, bool) {
+	v, ok := r.values[key]
+	return v, ok
+}
+
+// Values returns every known key in declaration order; unset keys have an empty Source.
+// Secret values are redacted.
+func (r *Result) Values() []Value {
+	out := make([]Value, 0, len(r.order))
+	for _, k := range r.order {
+		v, ok := r.values[k]
+		if !ok {
+			v = Value{Key: k}
+		}
+		if v.Secret && v.Value != "" {
+			v.Value = Redacted
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+// Print writes the resolved configuration as a table of key, value and source.
+func (r *Result) Print(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+	for _, v := range r.Values() {
+		source := v.Source
+		if source == "" {
+			source = "unset"
+		} else if v.Origin != "" && v.Origin != v.Key {
+			source += " (" + v.Origin + ")"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", v.Key, v.Value, source)
+	}
+	_ = tw.Flush()
+}
+
+// FlagName converts an env key to a flag name, e.g. S3_ENDPOINT -> s3-endpoint.
+func FlagName(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
+
+// IsSecretKey reports whether a key name looks like it holds a secret; see redact.IsSensitiveKey.
+func IsSecretKey(key string) bool { return redact.IsSensitiveKey(key) }
+
+// readYAML flattens a YAML document into env keys: nested maps are joined with "_" and keys
+// are upper-cased with "-" and "." replaced by "_".
+func readYAML(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is chosen by the operator's own configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	out := map[string]string{}
+	flatten("", doc, out)
+	return out, nil
+}
+
+func flatten(prefix string, m map[string]any, out map[string]string) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		key := strings.NewReplacer("-", "_", ".", "_").Replace(strings.ToUpper(k))
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		switch v := m[k].(type) {
+		case map[string]any:
+			flatten(key, v, out)
+		case []any:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				items = append(items, fmt.Sprint(item))
+			}
+			out[key] = strings.Join(items, ",")
+		case nil:
+			out[key] = ""
+		default:
+			out[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+func programName() string {
+	if len(os.Args) > 0 {
+		return os.Args[0]
+	}
+	return "operator"
+}
+
+// flagValue records whether a flag was given so unset flags do not override other sources.
+type flagValue struct {
+	value  string
+	set    bool
+	isBool bool
+}
+
+func (f *flagValue) String() string { return f.value }
+
+func (f *flagValue) Set(s string) error {
+	f.value, f.set = s, true
+	return nil
+}
+
+func (f *flagValue) IsBoolFlag() bool { return f.isBool }
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vitistack/common/pkg/settings/dotenv"
+)
+
+type testConfig struct {
+	Name      string        `env:"NAME" default:"operator"`
+	Timeout   time.Duration `env:"TIMEOUT" default:"30s"`
+	Endpoint  string        `env:"S3_ENDPOINT"`
+	SecretKey string        `env:"S3_SECRET_KEY"`
+	Region    string        `env:"S3_REGION"`
+	Debug     bool          `env:"DEBUG"`
+}
+
+func TestLoadPrecedenceAndProvenance(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	file := filepath.Join(dir, "config.yaml")
+	writeFile(t, file, "timeout: 1m\ns3:\n  endpoint: https://file.example.com\n  region: file-region\n")
+	writeFile(t, filepath.Join(dir, ".env"), "S3_REGION=dotenv-region\nS3_SECRET_KEY=from-dotenv\n")
+
+	processEnv := map[string]string{"S3_SECRET_KEY": "from-env", "NAME": "from-env"}
+	var cfg testConfig
+	res, err := Load(&cfg, Options{
+		ConfigFile: file,
+		Args:       []string{"--name", "from-flag", "--debug", "--print-config"},
+		Lookup: func(k string) (string, bool) {
+			v, ok := processEnv[k]
+			return v, ok
+		},
+	})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	if cfg.Name != "from-flag" || !cfg.Debug || cfg.Timeout != time.Minute ||
+		cfg.Endpoint != "https://file.example.com" || cfg.Region != "dotenv-region" || cfg.SecretKey != "from-env" {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+	wantSources := map[string]string{
+		"NAME": SourceFlag, "TIMEOUT": SourceFile, "S3_ENDPOINT": SourceFile,
+		"S3_REGION": SourceDotEnv, "S3_SECRET_KEY": SourceEnv, "DEBUG": SourceFlag,
+	}
+	for k, want := range wantSources {
+		if v, _ := res.Get(k); v.Source != want {
+			t.Errorf("source of %s = %q, want %q", k, v.Source, want)
+		}
+	}
+	if !res.PrintRequested {
+		t.Errorf("PrintRequested = false")
+	}
+
+	var buf bytes.Buffer
+	res.Print(&buf)
+	out := buf.String()
+	if strings.Contains(out, "from-env\t") || strings.Contains(out, "S3_SECRET_KEY  from-env") {
+		t.Errorf("secret leaked in output:\n%s", out)
+	}
+	if !strings.Contains(out, Redacted) || !strings.Contains(out, "file ("+file+")") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	var cfg testConfig
+	res, err := Load(&cfg, Options{SkipDotEnv: true, Args: []string{}, Lookup: func(string) (string, bool) { return "", false }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Name != "operator" {
+		t.Fatalf("Name = %q", cfg.Name)
+	}
+	if v, _ := res.Get("NAME"); v.Source != SourceDefault {
+		t.Fatalf("NAME source = %q", v.Source)
+	}
+}
+
+func TestLoadKeepsGoingOnPartialErrors(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	file := filepath.Join(dir, "config.yaml")
+	writeFile(t, file, "s3:\n  endpoint: https://file.example.com\n  bucket: typo\n")
+	writeFile(t, filepath.Join(dir, ".env"), "S3_REGION=dotenv-region\nNAME_FILE="+filepath.Join(dir, "name")+"\n")
+	writeFile(t, filepath.Join(dir, ".env.local"), "NOT VALID\n")
+	writeFile(t, filepath.Join(dir, "name"), "from-file\n")
+
+	var cfg testConfig
+	res, err := Load(&cfg, Options{
+		ConfigFile: file,
+		Args:       []string{},
+		DotEnv:     dotenv.Options{FileSecrets: []string{"NAME"}},
+		Lookup:     func(string) (string, bool) { return "", false },
+	})
+	if !errors.Is(err, ErrUnknownKeys) || !strings.Contains(err.Error(), "S3_BUCKET") {
+		t.Errorf("Load() error = %v, want unknown key S3_BUCKET", err)
+	}
+	if !strings.Contains(err.Error(), ".env.local") {
+		t.Errorf("Load() error = %v, want the .env.local parse error", err)
+	}
+	if res == nil || cfg.Endpoint != "https://file.example.com" || cfg.Region != "dotenv-region" || cfg.Name != "from-file" {
+		t.Fatalf("valid sources must still be applied: %+v", cfg)
+	}
+	if v, _ := res.Get("NAME"); !v.Secret {
+		t.Errorf("NAME from a secret file must be marked secret: %+v", v)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
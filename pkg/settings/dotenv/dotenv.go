@@ -1,46 +1,298 @@
 package dotenv
 
 import (
+	"errors"
 	"fmt"
 	"os"
-
-	"maps"
 	"path/filepath"
-
-	"github.com/joho/godotenv"
+	"slices"
+	"sort"
+	"strings"
 )
 
+// Options controls which files Read and Load use and how they are applied.
+type Options struct {
+	// Paths are read in order instead of discovering files. A missing explicit path is an error.
+	Paths []string
+	// Dir is where .env files are discovered (default: the working directory).
+	Dir string
+	// SearchUpwards also looks in parent directories and the executable's directory, like
+	// LoadDotEnv. Prefer explicit Paths or Dir: the upward search may pick up a stray .env.
+	SearchUpwards bool
+	// Env selects .env-<Env> and .env-<Env>.local (default: the ENV variable).
+	Env string
+	// Override lets file values replace variables already set in the process environment.
+	Override bool
+	// FileSecrets lists the keys FOO that may be read from the file named by FOO_FILE, as with
+	// Kubernetes secret mounts (FOO_FILE=/run/secrets/foo). Keys marked secret in Schema are included.
+	// Other *_FILE variables, such as LOG_FILE, are ordinary settings.
+	FileSecrets []string
+	// Schema is a .env.example or YAML schema file (see LoadSchema) the resolved variables must
+	// satisfy. With SearchUpwards a relative path is searched for like the .env files.
+	Schema string
+}
+
+// Entry is a resolved variable and where it came from.
+type Entry struct {
+	Key   string
+	Value string
+	// Source is the .env file that supplied the value, or the secret file for FOO_FILE keys.
+	Source string
+	// Secret is set for values read through the FOO_FILE convention.
+	Secret bool
+}
+
+// Report describes what Read or Load found.
+type Report struct {
+	// Files are the .env files read, in order of increasing precedence.
+	Files []string
+	// Entries holds the final value of every key defined by the files or secret files.
+	Entries map[string]Entry
+	// Applied and Skipped list the keys Load set in, or left alone because they already were
+	// set in, the process environment. Both are sorted.
+	Applied []string
+	Skipped []string
+	// Errors lists the .env files that did not parse and the secret files that could not be
+	// read. They were skipped; everything else was used.
+	Errors []error
+}
+
+// Source returns the file that supplied key, or "" if the key did not come from a file.
+func (r *Report) Source(key string) string { return r.Entries[key].Source }
+
+// String lists every key with its source; values are not included.
+func (r *Report) String() string {
+	keys := make([]string, 0, len(r.Entries))
+	for k := range r.Entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s <- %s\n", k, r.Entries[k].Source)
+	}
+	return b.String()
+}
+
 // loadDotEnv loads .env and optional .env-<ENV> without overriding existing OS env vars.
+// Files that cannot be parsed are skipped; the others are still applied.
 func LoadDotEnv() {
-	// Determine environment name from ENV variable (if any)
-	env := os.Getenv("ENV")
+	_, _ = Load(Options{SearchUpwards: true})
+}
+
+// Load reads the files selected by opts and sets their variables in the process environment.
+// Existing variables are kept unless opts.Override is set.
+//
+// Files that do not parse and secret files that cannot be read are skipped: everything else is
+// applied and the problems are returned with the report. Nothing is applied when an explicit
+// path or the schema cannot be read, or when the schema is not satisfied.
+func Load(opts Options) (*Report, error) {
+	r, err := opts.read()
+	if err != nil {
+		return r, r.err(err)
+	}
+	for k, e := range r.Entries {
+		if _, exists := os.LookupEnv(k); exists && !opts.Override {
+			r.Skipped = append(r.Skipped, k)
+			continue
+		}
+		if err := os.Setenv(k, e.Value); err != nil {
+			return r, fmt.Errorf("failed to set %s: %w", k, err)
+		}
+		r.Applied = append(r.Applied, k)
+	}
+	sort.Strings(r.Applied)
+	sort.Strings(r.Skipped)
+	return r, r.err(nil)
+}
+
+// Read resolves the files selected by opts without touching the process environment.
+//
+// Files are parsed with godotenv and read in order .env, .env.local, .env-<ENV>,
+// .env-<ENV>.local; later files override earlier ones. ${VAR} and $VAR are expanded against keys
+// from earlier lines and files and the process environment. For every key in opts.FileSecrets
+// or marked secret in the schema, FOO is read from the file named by FOO_FILE (from the files or
+// the process environment); the secret file wins over FOO in the .env files, but not over FOO
+// set in the process environment unless opts.Override is set.
+//
+// A file that does not parse or a secret file that cannot be read does not stop Read: it is
+// listed in Report.Errors and returned with the report of everything else. If opts.Schema is set
+// the result, combined with the process environment, is validated and a *SchemaError listing
+// every missing or invalid key is returned as well.
+func Read(opts Options) (*Report, error) {
+	r, err := opts.read()
+	if r == nil {
+		return nil, err
+	}
+	return r, r.err(err)
+}
+
+// err joins the skipped files in r.Errors with err.
+func (r *Report) err(err error) error {
+	if r == nil {
+		return err
+	}
+	return errors.Join(append(slices.Clone(r.Errors), err)...)
+}
+
+// read returns the report and the error that makes it unusable: the report is nil when an
+// explicit path or the schema cannot be read, and not applied when the schema is not satisfied.
+// Skipped files are listed in Report.Errors.
+func (o *Options) read() (*Report, error) {
+	files, err := o.files()
+	if err != nil {
+		return nil, err
+	}
+	var schema *Schema
+	if o.Schema != "" {
+		if schema, err = o.loadSchema(); err != nil {
+			return nil, err
+		}
+	}
+	r := &Report{Files: files, Entries: map[string]Entry{}}
 
+	lookup := func(k string) (string, bool) {
+		if !o.Override {
+			if v, ok := os.LookupEnv(k); ok {
+				return v, true
+			}
+		}
+		if e, ok := r.Entries[k]; ok {
+			return e.Value, true
+		}
+		return os.LookupEnv(k)
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f) // #nosec G304 -- .env paths are chosen by the caller
+		if err != nil {
+			r.Errors = append(r.Errors, err)
+			continue
+		}
+		values, err := parse(data, lookup)
+		if err != nil {
+			r.Errors = append(r.Errors, fmt.Errorf("%s: %w", f, err))
+			continue
+		}
+		for k, v := range values {
+			r.Entries[k] = Entry{Key: k, Value: v, Source: f}
+		}
+	}
+
+	secrets := slices.Clone(o.FileSecrets)
+	if schema != nil {
+		for _, k := range schema.Keys {
+			if k.Secret {
+				secrets = append(secrets, k.Key)
+			}
+		}
+	}
+	r.Errors = append(r.Errors, r.readSecretFiles(secrets, o.Override)...)
+
+	if schema != nil {
+		if err := schema.Validate(lookup); err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
+
+func (o *Options) loadSchema() (*Schema, error) {
+	path := o.Schema
+	if o.SearchUpwards && !filepath.IsAbs(path) {
+		if o.Dir != "" {
+			if p, ok := findUpwards(o.Dir, path); ok {
+				path = p
+			}
+		} else if p, ok := findFileIfExists(path); ok {
+			path = p
+		}
+	}
+	return LoadSchema(path)
+}
+
+// readSecretFiles implements the FOO_FILE convention used with Kubernetes secret mounts for
+// the given keys. It returns one error per secret file that could not be read.
+func (r *Report) readSecretFiles(keys []string, override bool) []error {
+	slices.Sort(keys)
+	var errs []error
+	for _, key := range slices.Compact(keys) {
+		ref := key + "_FILE"
+		path, ok := os.LookupEnv(ref)
+		if e, inFile := r.Entries[ref]; inFile {
+			path, ok = e.Value, true
+		}
+		if !ok || path == "" {
+			continue
+		}
+		if _, inEnv := os.LookupEnv(key); inEnv && !override {
+			continue
+		}
+		data, err := os.ReadFile(path) // #nosec G304 -- path comes from the operator's own configuration
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ref, err))
+			continue
+		}
+		r.Entries[key] = Entry{Key: key, Value: strings.TrimRight(string(data), "\r\n"), Source: path, Secret: true}
+	}
+	return errs
+}
+
+// files returns the .env files to read, in order of increasing precedence.
+func (o *Options) files() ([]string, error) {
+	if len(o.Paths) > 0 {
+		out := make([]string, 0, len(o.Paths))
+		for _, p := range o.Paths {
+			if _, err := os.Stat(p); err != nil {
+				return nil, fmt.Errorf("dotenv file %s: %w", p, err)
+			}
+			abs, err := filepath.Abs(p)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, abs)
+		}
+		return out, nil
+	}
+
+	env := o.Env
+	if env == "" {
+		// Determine environment name from ENV variable (if any)
+		env = os.Getenv("ENV")
+	}
 	// Candidate files in load order (lower to higher precedence)
-	candidates := []string{".env"}
+	candidates := []string{".env", ".env.local"}
 	if env != "" {
-		candidates = append(candidates, fmt.Sprintf(".env-%s", env))
+		candidates = append(candidates, fmt.Sprintf(".env-%s", env), fmt.Sprintf(".env-%s.local", env))
 	}
 
-	// Resolve each file by searching upwards from CWD and executable dir; ignore if missing
-	// Merge values so that later files override earlier file values, but never override existing OS env
-	merged := map[string]string{}
+	dir := o.Dir
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		dir = wd
+	}
+	var files []string
 	for _, f := range candidates {
-		// Find file if it exists
-		if p, ok := findFileIfExists(f); ok {
-			// Read variables from file
-			if kv, err := godotenv.Read(p); err == nil {
-				// Merge with precedence: later files override earlier file values
-				maps.Copy(merged, kv)
+		if o.SearchUpwards && o.Dir == "" {
+			if p, ok := findFileIfExists(f); ok {
+				files = append(files, p)
 			}
+			continue
 		}
-	}
-
-	// Apply to process env only for variables that are not already set in OS
-	for k, v := range merged {
-		if _, exists := os.LookupEnv(k); !exists {
-			_ = os.Setenv(k, v)
+		if o.SearchUpwards {
+			if p, ok := findUpwards(dir, f); ok {
+				files = append(files, p)
+			}
+			continue
+		}
+		p := filepath.Join(dir, f)
+		if _, err := os.Stat(p); err == nil {
+			files = append(files, p)
 		}
 	}
+	return files, nil
 }
 
 // findFileIfExists searches for the given file name starting from useful roots
@@ -3,6 +3,7 @@ package dotenv
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -328,3 +329,166 @@ func TestFindFileIfExists_Nonexistent(t *testing.T) {
 		t.Errorf("findFileIfExists() should return false for nonexistent file")
 	}
 }
+
+func TestRead_ExpansionLocalAndReport(t *testing.T) {
+	dir := t.TempDir()
+	writeEnvFile(t, filepath.Join(dir, ".env"), "HOST=localhost\nPORT=5432\nDB_URL=postgres://${HOST}:$PORT/app\nLITERAL='${HOST}'\n"+
+		"PASSWORD='p\"a$s\\w'\n")
+	writeEnvFile(t, filepath.Join(dir, ".env-prod"), "HOST=db.prod\nDB_URL=\"postgres://${HOST}:${PORT}/${DB_NAME}\"\nDSN=\"user:${PASSWORD}@db\"\n")
+	writeEnvFile(t, filepath.Join(dir, ".env.local"), "PORT=6543\n")
+	t.Setenv("DB_NAME", "prod")
+
+	r, err := Read(Options{Dir: dir, Env: "prod"})
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	want := map[string]string{
+		"HOST":    "db.prod",
+		"PORT":    "6543",
+		"DB_URL":  "postgres://db.prod:6543/prod",
+		"LITERAL": "${HOST}",
+		"DSN":     `user:p"a$s\w@db`,
+	}
+	for k, v := range want {
+		if got := r.Entries[k].Value; got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+	if src := r.Source("PORT"); filepath.Base(src) != ".env.local" {
+		t.Errorf("Source(PORT) = %q, want .env.local", src)
+	}
+	if len(r.Files) != 3 {
+		t.Errorf("Files = %v", r.Files)
+	}
+}
+
+func TestRead_NoUpwardSearchByDefault(t *testing.T) {
+	parent := t.TempDir()
+	child := filepath.Join(parent, "child")
+	if err := os.Mkdir(child, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeEnvFile(t, filepath.Join(parent, ".env"), "STRAY=yes\n")
+
+	r, err := Read(Options{Dir: child})
+	if err != nil || len(r.Files) != 0 {
+		t.Fatalf("expected no files, got %v, %v", r, err)
+	}
+	if _, err := Read(Options{Paths: []string{filepath.Join(child, ".env")}}); err == nil {
+		t.Fatalf("expected error for missing explicit path")
+	}
+}
+
+func TestLoad_FileSecrets(t *testing.T) {
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "s3-secret")
+	writeEnvFile(t, secret, "s3cr3t\n")
+	writeEnvFile(t, filepath.Join(dir, "app.env"), "S3_SECRET_KEY_FILE="+secret+"\n")
+	t.Setenv("TEST_TOKEN_FILE", secret)
+	t.Setenv("S3_SECRET_KEY", "")
+	_ = os.Unsetenv("S3_SECRET_KEY")
+	t.Setenv("TEST_TOKEN", "")
+	_ = os.Unsetenv("TEST_TOKEN")
+	// Load sets S3_SECRET_KEY_FILE itself; t.Setenv restores it afterwards.
+	t.Setenv("S3_SECRET_KEY_FILE", "")
+	_ = os.Unsetenv("S3_SECRET_KEY_FILE")
+
+	r, err := Load(Options{Paths: []string{filepath.Join(dir, "app.env")}, FileSecrets: []string{"S3_SECRET_KEY", "TEST_TOKEN"}})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if got := os.Getenv("S3_SECRET_KEY"); got != "s3cr3t" {
+		t.Errorf("S3_SECRET_KEY = %q", got)
+	}
+	if got := os.Getenv("TEST_TOKEN"); got != "s3cr3t" {
+		t.Errorf("TEST_TOKEN = %q", got)
+	}
+	if e := r.Entries["S3_SECRET_KEY"]; !e.Secret || e.Source != secret {
+		t.Errorf("entry = %+v", e)
+	}
+}
+
+func TestLoad_FileSecretsOnlyForDeclaredKeys(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "app.log")
+	writeEnvFile(t, logFile, "log line\n")
+	writeEnvFile(t, filepath.Join(dir, ".env"), "S3_ENDPOINT=https://s3.local\n")
+	writeEnvFile(t, filepath.Join(dir, ".env.example"), "# @type string\nS3_ENDPOINT=\n# @secret\nS3_SECRET_KEY=\nS3_REGION=\n")
+	t.Setenv("LOG_FILE", logFile)
+	t.Setenv("S3_SECRET_KEY_FILE", filepath.Join(dir, "missing"))
+	t.Setenv("S3_REGION_FILE", logFile)
+	for _, k := range []string{"LOG", "S3_ENDPOINT", "S3_SECRET_KEY", "S3_REGION"} {
+		t.Setenv(k, "")
+		_ = os.Unsetenv(k)
+	}
+
+	// S3_SECRET_KEY is marked secret in the schema, so its missing secret file is reported, but
+	// the .env values are still applied. LOG_FILE and S3_REGION_FILE are ordinary settings.
+	r, err := Load(Options{Dir: dir, Schema: filepath.Join(dir, ".env.example")})
+	if err == nil || !strings.Contains(err.Error(), "S3_SECRET_KEY_FILE") || strings.Contains(err.Error(), "LOG_FILE") {
+		t.Errorf("Load() error = %v, want only the S3_SECRET_KEY_FILE error", err)
+	}
+	if r == nil || os.Getenv("S3_ENDPOINT") != "https://s3.local" {
+		t.Errorf("S3_ENDPOINT = %q, want .env values applied despite the secret error", os.Getenv("S3_ENDPOINT"))
+	}
+	for _, k := range []string{"LOG", "S3_REGION"} {
+		if _, set := os.LookupEnv(k); set {
+			t.Errorf("%s should not be read from %s_FILE", k, k)
+		}
+	}
+}
+
+func TestRead_SecretFileWinsOverDotEnv(t *testing.T) {
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "token")
+	writeEnvFile(t, secret, "from-secret\n")
+	writeEnvFile(t, filepath.Join(dir, ".env"), "API_TOKEN=from-dotenv\nAPI_TOKEN_FILE="+secret+"\n")
+	t.Setenv("API_TOKEN", "")
+	_ = os.Unsetenv("API_TOKEN")
+
+	r, err := Read(Options{Dir: dir, FileSecrets: []string{"API_TOKEN"}})
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if e := r.Entries["API_TOKEN"]; e.Value != "from-secret" || !e.Secret || e.Source != secret {
+		t.Errorf("API_TOKEN = %+v, want the secret file", e)
+	}
+}
+
+func TestLoadDotEnv_SkipsUnparsableFile(t *testing.T) {
+	tmpDir, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	// A broken .env-<ENV> file is skipped on its own, as it was with godotenv.
+	writeEnvFile(t, filepath.Join(tmpDir, ".env"), "GOOD_VAR=good\n")
+	writeEnvFile(t, filepath.Join(tmpDir, ".env-broken"), "BROKEN_VAR=\"unterminated\n")
+	t.Setenv("ENV", "broken")
+	for _, k := range []string{"GOOD_VAR", "BROKEN_VAR"} {
+		t.Setenv(k, "")
+		_ = os.Unsetenv(k)
+	}
+
+	LoadDotEnv()
+
+	if val := os.Getenv("GOOD_VAR"); val != "good" {
+		t.Errorf("GOOD_VAR = %q, want %q", val, "good")
+	}
+	if _, set := os.LookupEnv("BROKEN_VAR"); set {
+		t.Errorf("BROKEN_VAR should not be set from an unparsable file")
+	}
+
+	r, err := Read(Options{Dir: tmpDir})
+	if err == nil || !strings.Contains(err.Error(), ".env-broken") || r.Entries["GOOD_VAR"].Value != "good" {
+		t.Errorf("Read() = %v, %v; want the parse error and the other files", r, err)
+	}
+	if len(r.Errors) != 1 {
+		t.Errorf("Report.Errors = %v, want the .env-broken error", r.Errors)
+	}
+}
+
+func writeEnvFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,50 @@
+package dotenv
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// refPattern finds the variables godotenv expands: $VAR and ${VAR}.
+var refPattern = regexp.MustCompile(`\$\{?([A-Z0-9_]+)`)
+
+// parse returns the assignments in data. godotenv expands $VAR and ${VAR} in unquoted and
+// double-quoted values, but only against keys defined earlier in the same file; unknown
+// variables become empty. So that values from earlier files and the process environment are
+// expanded as well, the variables data refers to are resolved with lookup and prepended to it.
+func parse(data []byte, lookup func(string) (string, bool)) (map[string]string, error) {
+	own, err := godotenv.UnmarshalBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	var prelude strings.Builder
+	seen := map[string]bool{}
+	for _, m := range refPattern.FindAllSubmatch(data, -1) {
+		name := string(m[1])
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if v, ok := lookup(name); ok {
+			prelude.WriteString(name + "=" + quote(v) + "\n")
+		}
+	}
+	if prelude.Len() == 0 {
+		return own, nil
+	}
+	all, err := godotenv.UnmarshalBytes(append([]byte(prelude.String()), data...))
+	if err != nil {
+		return nil, err
+	}
+	for k := range own {
+		own[k] = all[k]
+	}
+	return own, nil
+}
+
+// quote returns v as a double-quoted godotenv value that parses back to v unexpanded.
+func quote(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`, "\r", `\r`).Replace(v) + `"`
+}
@@ -0,0 +1,282 @@
+package dotenv
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Value types understood by a Schema.
+const (
+	TypeString   = "string"
+	TypeBool     = "bool"
+	TypeInt      = "int"
+	TypeFloat    = "float"
+	TypeDuration = "duration"
+	TypeURL      = "url"
+)
+
+// KeySpec describes one variable in a Schema.
+type KeySpec struct {
+	Key      string
+	Required bool
+	// Type is one of the Type* constants; empty means TypeString.
+	Type string
+	// Allowed lists the accepted values; empty means any value of Type.
+	Allowed []string
+	// Secret marks a key that may be read from the file named by KEY_FILE (see
+	// Options.FileSecrets).
+	Secret bool
+}
+
+// Schema lists the variables an application expects.
+type Schema struct {
+	// Source is the file the schema was read from.
+	Source string
+	Keys   []KeySpec
+}
+
+// InvalidKey is a variable whose value does not match its KeySpec.
+type InvalidKey struct {
+	Key string
+	Err error
+}
+
+// SchemaError lists every missing and invalid variable found by Schema.Validate.
+type SchemaError struct {
+	Source  string
+	Missing []string
+	Invalid []InvalidKey
+}
+
+func (e *SchemaError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "environment does not match %s", e.Source)
+	if len(e.Missing) > 0 {
+		fmt.Fprintf(&b, "\n  missing required keys: %s", strings.Join(e.Missing, ", "))
+	}
+	for _, ik := range e.Invalid {
+		fmt.Fprintf(&b, "\n  invalid %s: %v", ik.Key, ik.Err)
+	}
+	return b.String()
+}
+
+// LoadSchema reads a schema from a .env.example style file or, for .yaml, .yml and .json files,
+// from a map of keys to specs:
+//
+//	LOG_LEVEL:
+//	  required: true
+//	  enum: [debug, info, warn, error]
+//	LOG_JSON_ENABLED:
+//	  type: bool
+//	S3_SECRET_KEY:
+//	  secret: true
+//
+// In a .env.example file, keys are optional unless annotated. Annotations are comment lines
+// directly above the key:
+//
+//	# @required @enum debug,info,warn,error
+//	LOG_LEVEL=debug
+//	# @type bool
+//	LOG_JSON_ENABLED=false
+//	# @secret
+//	S3_SECRET_KEY=
+func LoadSchema(path string) (*Schema, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- schema path is chosen by the caller
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dotenv schema: %w", err)
+	}
+	var s *Schema
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		s, err = parseYAMLSchema(data)
+	default:
+		s, err = parseExample(string(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	s.Source = path
+	return s, nil
+}
+
+// Validate checks every key in the schema using lookup (e.g. os.LookupEnv) and returns a
+// *SchemaError listing all missing required keys and invalid values. Empty values count as
+// missing for required keys and are not type-checked for optional ones.
+func (s *Schema) Validate(lookup func(string) (string, bool)) error {
+	e := &SchemaError{Source: s.Source}
+	for _, k := range s.Keys {
+		v, _ := lookup(k.Key)
+		if v == "" {
+			if k.Required {
+				e.Missing = append(e.Missing, k.Key)
+			}
+			continue
+		}
+		if err := k.check(v); err != nil {
+			e.Invalid = append(e.Invalid, InvalidKey{Key: k.Key, Err: err})
+		}
+	}
+	if len(e.Missing) == 0 && len(e.Invalid) == 0 {
+		return nil
+	}
+	return e
+}
+
+// check validates v; errors do not include the value so secrets are never echoed.
+func (k *KeySpec) check(v string) error {
+	var err error
+	switch k.Type {
+	case "", TypeString:
+	case TypeBool:
+		_, err = strconv.ParseBool(v)
+	case TypeInt:
+		_, err = strconv.ParseInt(v, 10, 64)
+	case TypeFloat:
+		_, err = strconv.ParseFloat(v, 64)
+	case TypeDuration:
+		_, err = time.ParseDuration(v)
+	case TypeURL:
+		var u *url.URL
+		if u, err = url.Parse(v); err == nil && (u.Scheme == "" || u.Host == "") {
+			err = fmt.Errorf("not absolute")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("must be a %s", k.Type)
+	}
+	if len(k.Allowed) > 0 && !slices.Contains(k.Allowed, v) {
+		return fmt.Errorf("must be one of %s", strings.Join(k.Allowed, ", "))
+	}
+	return nil
+}
+
+func validType(t string) bool {
+	switch t {
+	case "", TypeString, TypeBool, TypeInt, TypeFloat, TypeDuration, TypeURL:
+		return true
+	}
+	return false
+}
+
+// parseExample reads keys and @ annotations from a .env.example file. A blank line or a
+// KEY= line ends the annotations that apply to the next key.
+func parseExample(src string) (*Schema, error) {
+	s := &Schema{}
+	var pending KeySpec
+	for i, line := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			pending = KeySpec{}
+		case strings.HasPrefix(line, "#"):
+			if err := pending.annotate(strings.TrimSpace(strings.TrimPrefix(line, "#"))); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		default:
+			key, _, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+			key = strings.TrimSpace(key)
+			if !ok || !validKey(key) {
+				continue // continuation of a multi-line value
+			}
+			pending.Key = key
+			s.Keys = append(s.Keys, pending)
+			pending = KeySpec{}
+		}
+	}
+	return s, nil
+}
+
+// annotate applies @required, @optional, @secret, @type <type> and @enum <a,b,c> from a comment.
+// Comments without a leading @ are documentation and ignored.
+func (k *KeySpec) annotate(comment string) error {
+	if !strings.HasPrefix(comment, "@") {
+		return nil
+	}
+	fields := strings.Fields(comment)
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "@required":
+			k.Required = true
+		case "@optional":
+			k.Required = false
+		case "@secret":
+			k.Secret = true
+		case "@type", "@enum":
+			if i+1 == len(fields) {
+				return fmt.Errorf("%s needs a value", fields[i])
+			}
+			i++
+			if fields[i-1] == "@enum" {
+				k.Allowed = splitList(fields[i])
+				continue
+			}
+			if !validType(fields[i]) {
+				return fmt.Errorf("unknown type %q", fields[i])
+			}
+			k.Type = fields[i]
+		default:
+			return fmt.Errorf("unknown annotation %q", fields[i])
+		}
+	}
+	return nil
+}
+
+func parseYAMLSchema(data []byte) (*Schema, error) {
+	var doc map[string]struct {
+		Required bool     `yaml:"required"`
+		Type     string   `yaml:"type"`
+		Enum     []string `yaml:"enum"`
+		Secret   bool     `yaml:"secret"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(doc))
+	for k := range doc {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	s := &Schema{}
+	for _, k := range keys {
+		spec := doc[k]
+		if !validType(spec.Type) {
+			return nil, fmt.Errorf("%s: unknown type %q", k, spec.Type)
+		}
+		s.Keys = append(s.Keys, KeySpec{Key: k, Required: spec.Required, Type: spec.Type, Allowed: spec.Enum, Secret: spec.Secret})
+	}
+	return s, nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func validKey(k string) bool {
+	if k == "" {
+		return false
+	}
+	for i, r := range k {
+		switch {
+		case r == '_' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z':
+		case i > 0 && (r >= '0' && r <= '9' || r == '.' || r == '-'):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package dotenv
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadSchema_Example(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env.example")
+	writeEnvFile(t, path, `# Logging
+
+# @required @enum debug,info,warn,error
+# Minimum log level.
+LOG_LEVEL=debug
+# @type bool
+LOG_JSON_ENABLED=false
+S3_REGION=""
+
+# @type duration
+# @required
+TIMEOUT=30s
+# @secret
+S3_SECRET_KEY=
+`)
+	s, err := LoadSchema(path)
+	if err != nil {
+		t.Fatalf("LoadSchema() unexpected error: %v", err)
+	}
+	want := []KeySpec{
+		{Key: "LOG_LEVEL", Required: true, Allowed: []string{"debug", "info", "warn", "error"}},
+		{Key: "LOG_JSON_ENABLED", Type: TypeBool},
+		{Key: "S3_REGION"},
+		{Key: "TIMEOUT", Required: true, Type: TypeDuration},
+		{Key: "S3_SECRET_KEY", Secret: true},
+	}
+	if len(s.Keys) != len(want) {
+		t.Fatalf("Keys = %+v", s.Keys)
+	}
+	for i, k := range s.Keys {
+		w := want[i]
+		if k.Key != w.Key || k.Required != w.Required || k.Type != w.Type || k.Secret != w.Secret || !slices.Equal(k.Allowed, w.Allowed) {
+			t.Errorf("Keys[%d] = %+v, want %+v", i, k, w)
+		}
+	}
+
+	writeEnvFile(t, path, "# @type number\nPORT=1\n")
+	if _, err := LoadSchema(path); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("expected line error for unknown type, got %v", err)
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "schema.yaml")
+	writeEnvFile(t, path, `
+LOG_LEVEL:
+  required: true
+  enum: [debug, info]
+LOG_JSON_ENABLED:
+  type: bool
+S3_ENDPOINT:
+  required: true
+  type: url
+PORT:
+  type: int
+`)
+	s, err := LoadSchema(path)
+	if err != nil {
+		t.Fatalf("LoadSchema() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		wantMissing []string
+		wantInvalid []string
+	}{
+		{
+			name: "valid",
+			env:  map[string]string{"LOG_LEVEL": "info", "S3_ENDPOINT": "https://s3.local", "PORT": "9000"},
+		},
+		{
+			name:        "missing and empty",
+			env:         map[string]string{"LOG_LEVEL": ""},
+			wantMissing: []string{"LOG_LEVEL", "S3_ENDPOINT"},
+		},
+		{
+			name:        "invalid values",
+			env:         map[string]string{"LOG_LEVEL": "verbose", "LOG_JSON_ENABLED": "yes please", "S3_ENDPOINT": "localhost:9000", "PORT": "x"},
+			wantInvalid: []string{"LOG_JSON_ENABLED", "LOG_LEVEL", "PORT", "S3_ENDPOINT"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Validate(func(k string) (string, bool) {
+				v, ok := tt.env[k]
+				return v, ok
+			})
+			if tt.wantMissing == nil && tt.wantInvalid == nil {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			var se *SchemaError
+			if !errors.As(err, &se) {
+				t.Fatalf("Validate() error = %v, want *SchemaError", err)
+			}
+			if !slices.Equal(se.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", se.Missing, tt.wantMissing)
+			}
+			var invalid []string
+			for _, ik := range se.Invalid {
+				invalid = append(invalid, ik.Key)
+			}
+			if !slices.Equal(invalid, tt.wantInvalid) {
+				t.Errorf("Invalid = %v, want %v", invalid, tt.wantInvalid)
+			}
+		})
+	}
+}
+
+func TestLoad_SchemaFailsBeforeApplying(t *testing.T) {
+	dir := t.TempDir()
+	writeEnvFile(t, filepath.Join(dir, ".env"), "SCHEMA_TEST_LEVEL=info\n")
+	writeEnvFile(t, filepath.Join(dir, ".env.example"), "SCHEMA_TEST_LEVEL=\n# @required\nSCHEMA_TEST_TOKEN=\n")
+	t.Setenv("SCHEMA_TEST_LEVEL", "")
+	t.Setenv("SCHEMA_TEST_TOKEN", "")
+
+	r, err := Load(Options{Dir: dir, Schema: filepath.Join(dir, ".env.example")})
+	var se *SchemaError
+	if !errors.As(err, &se) || !slices.Equal(se.Missing, []string{"SCHEMA_TEST_TOKEN"}) {
+		t.Fatalf("Load() error = %v, want missing SCHEMA_TEST_TOKEN", err)
+	}
+	if r == nil || len(r.Applied) != 0 {
+		t.Errorf("expected report with nothing applied, got %+v", r)
+	}
+	if !strings.Contains(err.Error(), "missing required keys: SCHEMA_TEST_TOKEN") {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
+
+func TestLoadSchema_RepositoryExample(t *testing.T) {
+	s, err := LoadSchema(filepath.Join("..", "..", "..", ".env.example"))
+	if err != nil {
+		t.Fatalf("LoadSchema() unexpected error: %v", err)
+	}
+	i := slices.IndexFunc(s.Keys, func(k KeySpec) bool { return k.Key == "LOG_LEVEL" })
+	// LOG_LEVEL has a default, so it is optional but restricted to the known levels.
+	if i < 0 || s.Keys[i].Required || !slices.Contains(s.Keys[i].Allowed, "debug") {
+		t.Errorf("expected LOG_LEVEL to be an optional enum, got %+v", s.Keys)
+	}
+	// S3_USE_MOCK selects between the mock and a real S3 endpoint and has no default.
+	var se *SchemaError
+	err = s.Validate(func(string) (string, bool) { return "", false })
+	if !errors.As(err, &se) || !slices.Equal(se.Missing, []string{"S3_USE_MOCK"}) {
+		t.Errorf("Validate() of an empty environment = %v, want only S3_USE_MOCK missing", err)
+	}
+	mock := map[string]string{"S3_USE_MOCK": "true"}
+	if err := s.Validate(func(k string) (string, bool) { v, ok := mock[k]; return v, ok }); err != nil {
+		t.Errorf("S3_USE_MOCK=true should satisfy .env.example: %v", err)
+	}
+}
@@ -58,6 +58,10 @@ type ClusterStorageStatus struct {
 
 	Message string `json:"message,omitempty"`
 
+	PhaseReason string `json:"phaseReason,omitempty"`
+
+	PhaseTransitionTime metav1.Time `json:"phaseTransitionTime,omitempty"`
+
 	Secret secretStatus `json:"secret,omitempty"`
 
 	GuestResource GuestResourceStatus `json:"guestResource,omitempty"`
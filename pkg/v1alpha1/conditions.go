@@ -0,0 +1,117 @@
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The project's condition structs predate metav1.Condition and differ slightly in shape.
+// The ToCondition/FromCondition pairs below convert them to and from metav1.Condition so
+// generic helpers (see pkg/operator/conditions) can apply the same rules to all of them.
+
+// ToCondition returns c as a metav1.Condition.
+func (c MachineCondition) ToCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:               c.Type,
+		Status:             metav1.ConditionStatus(c.Status),
+		Reason:             c.Reason,
+		Message:            c.Message,
+		LastTransitionTime: c.LastTransitionTime,
+	}
+}
+
+// FromCondition overwrites c with the fields of in.
+func (c *MachineCondition) FromCondition(in metav1.Condition) {
+	c.Type = in.Type
+	c.Status = string(in.Status)
+	c.Reason = in.Reason
+	c.Message = in.Message
+	c.LastTransitionTime = in.LastTransitionTime
+}
+
+// ToCondition returns c as a metav1.Condition.
+func (c ProviderCondition) ToCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:               c.Type,
+		Status:             metav1.ConditionStatus(c.Status),
+		Reason:             c.Reason,
+		Message:            c.Message,
+		LastTransitionTime: c.LastTransitionTime,
+	}
+}
+
+// FromCondition overwrites c with the fields of in.
+func (c *ProviderCondition) FromCondition(in metav1.Condition) {
+	c.Type = in.Type
+	c.Status = string(in.Status)
+	c.Reason = in.Reason
+	c.Message = in.Message
+	c.LastTransitionTime = in.LastTransitionTime
+}
+
+// ToCondition returns c as a metav1.Condition.
+func (c KubernetesProviderCondition) ToCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:               c.Type,
+		Status:             metav1.ConditionStatus(c.Status),
+		Reason:             c.Reason,
+		Message:            c.Message,
+		LastTransitionTime: c.LastTransitionTime,
+	}
+}
+
+// FromCondition overwrites c with the fields of in.
+func (c *KubernetesProviderCondition) FromCondition(in metav1.Condition) {
+	c.Type = in.Type
+	c.Status = string(in.Status)
+	c.Reason = in.Reason
+	c.Message = in.Message
+	c.LastTransitionTime = in.LastTransitionTime
+}
+
+// ToCondition returns c as a metav1.Condition.
+func (c EtcdBackupCondition) ToCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:               c.Type,
+		Status:             metav1.ConditionStatus(c.Status),
+		Reason:             c.Reason,
+		Message:            c.Message,
+		LastTransitionTime: c.LastTransitionTime,
+	}
+}
+
+// FromCondition overwrites c with the fields of in.
+func (c *EtcdBackupCondition) FromCondition(in metav1.Condition) {
+	c.Type = in.Type
+	c.Status = string(in.Status)
+	c.Reason = in.Reason
+	c.Message = in.Message
+	c.LastTransitionTime = in.LastTransitionTime
+}
+
+// ToCondition parses LastTransitionTime as RFC3339; an empty or unparsable value yields a zero time.
+func (c KubernetesClusterCondition) ToCondition() metav1.Condition {
+	out := metav1.Condition{
+		Type:    c.Type,
+		Status:  metav1.ConditionStatus(c.Status),
+		Reason:  c.Reason,
+		Message: c.Message,
+	}
+	if t, err := time.Parse(time.RFC3339, c.LastTransitionTime); err == nil {
+		out.LastTransitionTime = metav1.NewTime(t)
+	}
+	return out
+}
+
+// FromCondition formats LastTransitionTime as RFC3339; a zero time is stored as an empty string.
+func (c *KubernetesClusterCondition) FromCondition(in metav1.Condition) {
+	c.Type = in.Type
+	c.Status = string(in.Status)
+	c.Reason = in.Reason
+	c.Message = in.Message
+	c.LastTransitionTime = ""
+	if !in.LastTransitionTime.IsZero() {
+		c.LastTransitionTime = in.LastTransitionTime.UTC().Format(time.RFC3339)
+	}
+}
@@ -166,13 +166,26 @@ type KubernetesClusterAutoscalingSpec struct {
 // KubernetesClusterStatus represents the status of a Kubernetes cluster.
 // It contains the current state, phase, and conditions of the cluster.
 type KubernetesClusterStatus struct {
-	State      KubernetesClusterClusterState `json:"state"`
-	Phase      string                        `json:"phase"`             // Provisioning, Running, Deleting, Failed, Updating
-	Message    string                        `json:"message,omitempty"` // Human-readable message describing current activity
-	Workers    int                           `json:"workers,omitempty"` // Total number of worker machines
-	Conditions []KubernetesClusterCondition  `json:"conditions"`
+	State               KubernetesClusterClusterState `json:"state"`
+	Phase               string                        `json:"phase"`                         // Provisioning, Running, Deleting, Deleted, Failed, Updating
+	Message             string                        `json:"message,omitempty"`             // Human-readable message describing current activity
+	PhaseReason         string                        `json:"phaseReason,omitempty"`         // Reason for the last phase transition
+	PhaseTransitionTime metav1.Time                   `json:"phaseTransitionTime,omitempty"` // The last time the phase changed
+	Workers             int                           `json:"workers,omitempty"`             // Total number of worker machines
+	Conditions          []KubernetesClusterCondition  `json:"conditions"`
+	TeardownSteps       []TeardownStepStatus          `json:"teardownSteps,omitempty"` // Progress of the cleanup steps run during deletion
 }
 
+// Common phases for KubernetesCluster
+const (
+	KubernetesClusterPhaseProvisioning = "Provisioning"
+	KubernetesClusterPhaseRunning      = "Running"
+	KubernetesClusterPhaseUpdating     = "Updating"
+	KubernetesClusterPhaseDeleting     = "Deleting"
+	KubernetesClusterPhaseDeleted      = "Deleted"
+	KubernetesClusterPhaseFailed       = "Failed"
+)
+
 type KubernetesClusterClusterState struct {
 	Cluster       KubernetesClusterClusterDetails `json:"cluster"`
 	Versions      []KubernetesClusterVersion      `json:"versions"`
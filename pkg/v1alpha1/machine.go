@@ -296,6 +296,12 @@ type MachineStatus struct {
 	// Detailed status message
 	Message string `json:"message,omitempty"`
 
+	// Reason for the last phase transition
+	PhaseReason string `json:"phaseReason,omitempty"`
+
+	// The last time the phase changed
+	PhaseTransitionTime metav1.Time `json:"phaseTransitionTime,omitempty"`
+
 	// The unique identifier assigned by the provider
 	ProviderID string `json:"providerID,omitempty"`
 
@@ -370,6 +376,9 @@ type MachineStatus struct {
 
 	// Failure message if the machine failed to be created
 	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// Progress of the cleanup steps run while the machine is deleted
+	TeardownSteps []TeardownStepStatus `json:"teardownSteps,omitempty"`
 }
 
 type MachineDisk struct {
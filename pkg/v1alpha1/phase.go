@@ -0,0 +1,55 @@
+package v1alpha1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// GetPhase and SetPhase expose Status.Phase and Status.Message, and GetPhaseTransition and
+// SetPhaseTransition expose Status.PhaseReason and Status.PhaseTransitionTime, so the phase
+// state machine (see pkg/operator/phase) can drive objects of different kinds.
+
+func (m *Machine) GetPhase() string { return m.Status.Phase }
+
+func (m *Machine) SetPhase(phase, message string) {
+	m.Status.Phase = phase
+	m.Status.Message = message
+}
+
+func (m *Machine) GetPhaseTransition() (string, metav1.Time) {
+	return m.Status.PhaseReason, m.Status.PhaseTransitionTime
+}
+
+func (m *Machine) SetPhaseTransition(reason string, at metav1.Time) {
+	m.Status.PhaseReason = reason
+	m.Status.PhaseTransitionTime = at
+}
+
+func (k *KubernetesCluster) GetPhase() string { return k.Status.Phase }
+
+func (k *KubernetesCluster) SetPhase(phase, message string) {
+	k.Status.Phase = phase
+	k.Status.Message = message
+}
+
+func (k *KubernetesCluster) GetPhaseTransition() (string, metav1.Time) {
+	return k.Status.PhaseReason, k.Status.PhaseTransitionTime
+}
+
+func (k *KubernetesCluster) SetPhaseTransition(reason string, at metav1.Time) {
+	k.Status.PhaseReason = reason
+	k.Status.PhaseTransitionTime = at
+}
+
+func (c *ClusterStorage) GetPhase() string { return c.Status.Phase }
+
+func (c *ClusterStorage) SetPhase(phase, message string) {
+	c.Status.Phase = phase
+	c.Status.Message = message
+}
+
+func (c *ClusterStorage) GetPhaseTransition() (string, metav1.Time) {
+	return c.Status.PhaseReason, c.Status.PhaseTransitionTime
+}
+
+func (c *ClusterStorage) SetPhaseTransition(reason string, at metav1.Time) {
+	c.Status.PhaseReason = reason
+	c.Status.PhaseTransitionTime = at
+}
@@ -44,7 +44,7 @@ type ProxmoxConfigSpec struct {
 	Username string `json:"username,omitempty"`
 
 	// +kubebuilder:validation:Required
-	Token string `json:"token,omitempty"`
+	Token string `json:"token,omitempty" redact:"true"`
 
 	// +kubebuilder:validation:Required
 	Name string `json:"name,omitempty"`
@@ -0,0 +1,12 @@
+package v1alpha1
+
+// GetRetryCount and SetRetryCount expose Status.RetryCount so generic retry tracking
+// (see pkg/operator/reconcileutil) can persist attempts in the object's status.
+
+func (n *NetworkNamespace) GetRetryCount() int { return n.Status.RetryCount }
+
+func (n *NetworkNamespace) SetRetryCount(c int) { n.Status.RetryCount = c }
+
+func (c *ControlPlaneVirtualSharedIP) GetRetryCount() int { return c.Status.RetryCount }
+
+func (c *ControlPlaneVirtualSharedIP) SetRetryCount(n int) { c.Status.RetryCount = n }
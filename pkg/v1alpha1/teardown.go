@@ -0,0 +1,48 @@
+package v1alpha1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// TeardownStepState is the state of a cleanup step run while an object is deleted.
+type TeardownStepState string
+
+const (
+	TeardownStepPending   TeardownStepState = "Pending"
+	TeardownStepRunning   TeardownStepState = "Running"
+	TeardownStepSucceeded TeardownStepState = "Succeeded"
+	TeardownStepFailed    TeardownStepState = "Failed"
+)
+
+// TeardownStepStatus is the progress of a cleanup step run while an object is deleted
+// (see pkg/operator/finalizers).
+type TeardownStepStatus struct {
+	// Name of the step
+	Name string `json:"name"`
+
+	// State of the step (Pending, Running, Succeeded, Failed)
+	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+	State TeardownStepState `json:"state"`
+
+	// Number of attempts made so far
+	Attempts int `json:"attempts,omitempty"`
+
+	// Error of the last failed attempt
+	Message string `json:"message,omitempty"`
+
+	// The last time the state changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// GetTeardownSteps and SetTeardownSteps expose Status.TeardownSteps so the teardown
+// orchestrator (see pkg/operator/finalizers) can persist its progress in the object's status.
+
+func (m *Machine) GetTeardownSteps() []TeardownStepStatus { return m.Status.TeardownSteps }
+
+func (m *Machine) SetTeardownSteps(steps []TeardownStepStatus) { m.Status.TeardownSteps = steps }
+
+func (k *KubernetesCluster) GetTeardownSteps() []TeardownStepStatus {
+	return k.Status.TeardownSteps
+}
+
+func (k *KubernetesCluster) SetTeardownSteps(steps []TeardownStepStatus) {
+	k.Status.TeardownSteps = steps
+}
@@ -226,7 +226,7 @@ func (in *ClusterStorage) DeepCopyInto(out *ClusterStorage) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStorage.
@@ -393,6 +393,7 @@ func (in *ClusterStorageSpec) DeepCopy() *ClusterStorageSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterStorageStatus) DeepCopyInto(out *ClusterStorageStatus) {
 	*out = *in
+	in.PhaseTransitionTime.DeepCopyInto(&out.PhaseTransitionTime)
 	out.Secret = in.Secret
 	out.GuestResource = in.GuestResource
 }
@@ -1347,11 +1348,19 @@ func (in *KubernetesClusterSpecTopology) DeepCopy() *KubernetesClusterSpecTopolo
 func (in *KubernetesClusterStatus) DeepCopyInto(out *KubernetesClusterStatus) {
 	*out = *in
 	in.State.DeepCopyInto(&out.State)
+	in.PhaseTransitionTime.DeepCopyInto(&out.PhaseTransitionTime)
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]KubernetesClusterCondition, len(*in))
 		copy(*out, *in)
 	}
+	if in.TeardownSteps != nil {
+		in, out := &in.TeardownSteps, &out.TeardownSteps
+		*out = make([]TeardownStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesClusterStatus.
@@ -2523,6 +2532,7 @@ func (in *MachineSpecDisk) DeepCopy() *MachineSpecDisk {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineStatus) DeepCopyInto(out *MachineStatus) {
 	*out = *in
+	in.PhaseTransitionTime.DeepCopyInto(&out.PhaseTransitionTime)
 	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
 	if in.IPAddresses != nil {
 		in, out := &in.IPAddresses, &out.IPAddresses
@@ -2583,6 +2593,13 @@ func (in *MachineStatus) DeepCopyInto(out *MachineStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.TeardownSteps != nil {
+		in, out := &in.TeardownSteps, &out.TeardownSteps
+		*out = make([]TeardownStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineStatus.
@@ -3854,6 +3871,22 @@ func (in *SubnetInfo) DeepCopy() *SubnetInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeardownStepStatus) DeepCopyInto(out *TeardownStepStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeardownStepStatus.
+func (in *TeardownStepStatus) DeepCopy() *TeardownStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TeardownStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ThroughputRange) DeepCopyInto(out *ThroughputRange) {
 	*out = *in